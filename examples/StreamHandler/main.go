@@ -34,12 +34,12 @@ func main() {
 	handler.SetFilePermission(filePermission) // Set the file permission to 0644
 	handler.SetUseLock(true)                  // Use a lock to write to the file
 
-	// Defer stream handler flush
-	defer handler.Flush() // Flush the buffer before the program ends (to write logs that are still in the buffer)
-
 	// Create the logger
 	logger := logger.NewLogger()
 
+	// Defer logger close
+	defer logger.Close() // Flush the buffers and close the files before the program ends (to write logs that are still in the buffer)
+
 	// Add the handler to the logger
 	logger.AddHandler(handler)
 
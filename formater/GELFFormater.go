@@ -0,0 +1,101 @@
+package formater
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ZertyCraft/GoLogger/levels"
+	"github.com/ZertyCraft/GoLogger/record"
+)
+
+// GELFFormater is a formater that formats records as GELF 1.1 JSON messages (Graylog Extended Log Format).
+// The first line of the message is the `short_message`, and the whole message the `full_message` if it has several.
+// The level is mapped to its syslog severity, and the logger name, the caller and the fields
+// are sent as additional fields, prefixed with an underscore.
+type GELFFormater struct {
+	host string
+}
+
+// NewGELFFormater creates a new GELFFormater for the local host name.
+func NewGELFFormater() *GELFFormater {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unknown"
+	}
+
+	return &GELFFormater{host: host}
+}
+
+// SetHost sets the host of the messages.
+func (f *GELFFormater) SetHost(host string) {
+	f.host = host
+}
+
+// GetHost returns the host of the messages.
+func (f *GELFFormater) GetHost() string {
+	return f.host
+}
+
+// Format formats the message as a GELF message.
+func (f *GELFFormater) Format(level levels.Level, message string) (string, error) {
+	return f.FormatRecord(record.New(level, message))
+}
+
+// FormatRecord formats the record as a GELF message.
+func (f *GELFFormater) FormatRecord(rec record.Record) (string, error) {
+	microseconds := rec.Time.UnixMicro()
+	message := map[string]any{
+		"version":   "1.1",
+		"host":      f.host,
+		"timestamp": json.Number(fmt.Sprintf("%d.%06d", microseconds/1e6, microseconds%1e6)),
+		"level":     SyslogSeverity(rec.Level),
+	}
+
+	shortMessage, _, multiLine := strings.Cut(rec.Message, "\n")
+	message["short_message"] = shortMessage
+
+	if multiLine {
+		message["full_message"] = rec.Message
+	}
+
+	if rec.LoggerName != "" {
+		message["_logger"] = rec.LoggerName
+	}
+
+	if rec.HasCaller() {
+		message["_file"] = rec.Caller.File
+		message["_line"] = rec.Caller.Line
+	}
+
+	for _, field := range rec.Fields {
+		message[gelfFieldName(field.Key)] = JSONValue(field.Value)
+	}
+
+	data, err := json.Marshal(message)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode GELF message: %w", err)
+	}
+
+	return string(data), nil
+}
+
+// gelfFieldName returns the name of the additional field of the key: an underscore followed by
+// letters, digits, underscores, dashes and dots. The reserved `_id` is renamed `__id`.
+func gelfFieldName(key string) string {
+	name := "_" + strings.Map(func(char rune) rune {
+		if (char >= 'a' && char <= 'z') || (char >= 'A' && char <= 'Z') || (char >= '0' && char <= '9') ||
+			char == '_' || char == '-' || char == '.' {
+			return char
+		}
+
+		return '_'
+	}, key)
+
+	if name == "_id" {
+		return "__id"
+	}
+
+	return name
+}
@@ -0,0 +1,91 @@
+package formater
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/ZertyCraft/GoLogger/levels"
+	"github.com/ZertyCraft/GoLogger/record"
+)
+
+// JSONFormater is a formater that formats records as single-line JSON objects
+// with the `time`, `level`, `message`, `logger`, `caller` and `fields` keys.
+// The logger, caller and fields are omitted when empty.
+type JSONFormater struct{}
+
+// jsonRecord is the JSON representation of a record.
+type jsonRecord struct {
+	Time    string         `json:"time"`
+	Level   string         `json:"level"`
+	Message string         `json:"message"`
+	Logger  string         `json:"logger,omitempty"`
+	Caller  string         `json:"caller,omitempty"`
+	Fields  map[string]any `json:"fields,omitempty"`
+}
+
+// NewJSONFormater creates a new JSONFormater.
+func NewJSONFormater() *JSONFormater {
+	return &JSONFormater{}
+}
+
+// Format formats the message as a JSON object.
+func (f *JSONFormater) Format(level levels.Level, message string) (string, error) {
+	return f.FormatRecord(record.New(level, message))
+}
+
+// FormatRecord formats the record as a JSON object, with the time in RFC 3339 format.
+func (f *JSONFormater) FormatRecord(rec record.Record) (string, error) {
+	encoded := jsonRecord{
+		Time:    rec.Time.Format(time.RFC3339Nano),
+		Level:   rec.Level.String(),
+		Message: rec.Message,
+		Logger:  rec.LoggerName,
+		Caller:  "",
+		Fields:  JSONFields(rec.Fields),
+	}
+
+	if rec.HasCaller() {
+		encoded.Caller = rec.Caller.File + ":" + strconv.Itoa(rec.Caller.Line)
+	}
+
+	data, err := json.Marshal(encoded)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode record: %w", err)
+	}
+
+	return string(data), nil
+}
+
+// JSONFields returns the fields as a map that can be encoded in JSON, or nil if there are none.
+// Errors are replaced with their message, and the values that cannot be encoded with their `fmt` representation.
+func JSONFields(fields []record.Field) map[string]any {
+	if len(fields) == 0 {
+		return nil
+	}
+
+	values := make(map[string]any, len(fields))
+
+	for _, field := range fields {
+		values[field.Key] = JSONValue(field.Value)
+	}
+
+	return values
+}
+
+// JSONValue returns the value, or a representation of it that can be encoded in JSON.
+func JSONValue(value any) any {
+	switch value := value.(type) {
+	case nil, string, bool, int, int64, uint64, float64:
+		return value
+	case error:
+		return value.Error()
+	}
+
+	if _, err := json.Marshal(value); err != nil {
+		return fmt.Sprint(value)
+	}
+
+	return value
+}
@@ -2,9 +2,9 @@ package formater
 
 import (
 	"strings"
-	"time"
 
 	"github.com/ZertyCraft/GoLogger/levels"
+	"github.com/ZertyCraft/GoLogger/record"
 )
 
 // LineFormater is a formater that formats the message in a single line.
@@ -27,10 +27,15 @@ func NewLineFormater() *LineFormater {
 // - %l: the log level.
 // - %m: the message.
 func (f *LineFormater) Format(level levels.Level, message string) (string, error) {
+	return f.FormatRecord(record.New(level, message))
+}
+
+// FormatRecord formats the record like `Format`, using the time of the record for %d.
+func (f *LineFormater) FormatRecord(rec record.Record) (string, error) {
 	formatedMessage := f.format
-	formatedMessage = strings.ReplaceAll(formatedMessage, "%d", time.Now().Format("2006-01-02 15:04:05"))
-	formatedMessage = strings.ReplaceAll(formatedMessage, "%l", level.String())
-	formatedMessage = strings.ReplaceAll(formatedMessage, "%m", message)
+	formatedMessage = strings.ReplaceAll(formatedMessage, "%d", rec.Time.Format("2006-01-02 15:04:05"))
+	formatedMessage = strings.ReplaceAll(formatedMessage, "%l", rec.Level.String())
+	formatedMessage = strings.ReplaceAll(formatedMessage, "%m", rec.Message)
 
 	return formatedMessage, nil
 }
@@ -0,0 +1,22 @@
+package formater
+
+import (
+	"github.com/ZertyCraft/GoLogger/record"
+)
+
+// RecordFormater is an optional interface implemented by formaters that use the whole record
+// (time, logger name, fields) instead of only the level and the message.
+type RecordFormater interface {
+	Formater
+	FormatRecord(rec record.Record) (string, error)
+}
+
+// FormatRecord formats the record with the given formater,
+// using `FormatRecord` if the formater implements `RecordFormater`, and `Format` otherwise.
+func FormatRecord(formater Formater, rec record.Record) (string, error) {
+	if recordFormater, ok := formater.(RecordFormater); ok {
+		return recordFormater.FormatRecord(rec)
+	}
+
+	return formater.Format(rec.Level, rec.Message)
+}
@@ -0,0 +1,291 @@
+package formater
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/ZertyCraft/GoLogger/levels"
+	"github.com/ZertyCraft/GoLogger/record"
+)
+
+// Facility is a syslog facility (RFC 5424, section 6.2.1).
+type Facility int
+
+// Syslog facilities.
+const (
+	FacilityKern Facility = iota
+	FacilityUser
+	FacilityMail
+	FacilityDaemon
+	FacilityAuth
+	FacilitySyslog
+	FacilityLPR
+	FacilityNews
+	FacilityUUCP
+	FacilityCron
+	FacilityAuthPriv
+	FacilityFTP
+	FacilityNTP
+	FacilityAudit
+	FacilityAlert
+	FacilityClock
+	FacilityLocal0
+	FacilityLocal1
+	FacilityLocal2
+	FacilityLocal3
+	FacilityLocal4
+	FacilityLocal5
+	FacilityLocal6
+	FacilityLocal7
+)
+
+// Syslog severities used for the log levels (RFC 5424, section 6.2.1).
+const (
+	SeverityCritical = 2
+	SeverityError    = 3
+	SeverityWarning  = 4
+	SeverityInfo     = 6
+	SeverityDebug    = 7
+)
+
+// SyslogSeverity returns the syslog severity of the log level.
+func SyslogSeverity(level levels.Level) int {
+	switch level {
+	case levels.DEBUG:
+		return SeverityDebug
+	case levels.INFO:
+		return SeverityInfo
+	case levels.WARN:
+		return SeverityWarning
+	case levels.ERROR:
+		return SeverityError
+	case levels.CRITICAL:
+		return SeverityCritical
+	}
+
+	return SeverityInfo
+}
+
+// syslogFormater holds the header fields shared by the syslog formaters.
+type syslogFormater struct {
+	facility Facility
+	hostname string
+	appName  string
+	procID   string
+}
+
+// newSyslogFormater returns a syslogFormater for the user facility, the local host name,
+// the name of the executable and the current process id.
+func newSyslogFormater() syslogFormater {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = ""
+	}
+
+	return syslogFormater{
+		facility: FacilityUser,
+		hostname: hostname,
+		appName:  filepath.Base(os.Args[0]),
+		procID:   strconv.Itoa(os.Getpid()),
+	}
+}
+
+// SetFacility sets the facility of the messages.
+func (f *syslogFormater) SetFacility(facility Facility) {
+	f.facility = facility
+}
+
+// GetFacility returns the facility of the messages.
+func (f *syslogFormater) GetFacility() Facility {
+	return f.facility
+}
+
+// SetHostname sets the host name of the messages.
+func (f *syslogFormater) SetHostname(hostname string) {
+	f.hostname = hostname
+}
+
+// GetHostname returns the host name of the messages.
+func (f *syslogFormater) GetHostname() string {
+	return f.hostname
+}
+
+// SetAppName sets the application name (the tag in RFC 3164) of the messages.
+func (f *syslogFormater) SetAppName(appName string) {
+	f.appName = appName
+}
+
+// GetAppName returns the application name of the messages.
+func (f *syslogFormater) GetAppName() string {
+	return f.appName
+}
+
+// SetProcID sets the process id of the messages.
+func (f *syslogFormater) SetProcID(procID string) {
+	f.procID = procID
+}
+
+// GetProcID returns the process id of the messages.
+func (f *syslogFormater) GetProcID() string {
+	return f.procID
+}
+
+// priority returns the PRI part of a message of the given level.
+func (f *syslogFormater) priority(level levels.Level) string {
+	return "<" + strconv.Itoa(int(f.facility)*8+SyslogSeverity(level)) + ">"
+}
+
+// RFC5424Formater is a formater that formats records as RFC 5424 syslog messages.
+// The fields of the record are written as structured data.
+type RFC5424Formater struct {
+	syslogFormater
+	structuredDataID string
+}
+
+// defaultStructuredDataID is the default SD-ID of the structured data,
+// using the private enterprise number reserved for documentation.
+const defaultStructuredDataID = "fields@32473"
+
+// NewRFC5424Formater creates a new RFC5424Formater.
+func NewRFC5424Formater() *RFC5424Formater {
+	return &RFC5424Formater{
+		syslogFormater:   newSyslogFormater(),
+		structuredDataID: defaultStructuredDataID,
+	}
+}
+
+// SetStructuredDataID sets the SD-ID of the structured data element holding the fields.
+func (f *RFC5424Formater) SetStructuredDataID(structuredDataID string) {
+	f.structuredDataID = structuredDataID
+}
+
+// GetStructuredDataID returns the SD-ID of the structured data element holding the fields.
+func (f *RFC5424Formater) GetStructuredDataID() string {
+	return f.structuredDataID
+}
+
+// Format formats the message as an RFC 5424 syslog message.
+func (f *RFC5424Formater) Format(level levels.Level, message string) (string, error) {
+	return f.FormatRecord(record.New(level, message))
+}
+
+// FormatRecord formats the record as an RFC 5424 syslog message:
+// `<PRI>1 TIMESTAMP HOSTNAME APP-NAME PROCID MSGID STRUCTURED-DATA MSG`.
+func (f *RFC5424Formater) FormatRecord(rec record.Record) (string, error) {
+	var builder strings.Builder
+
+	builder.WriteString(f.priority(rec.Level))
+	builder.WriteString("1 ")
+	builder.WriteString(rec.Time.Format("2006-01-02T15:04:05.000000Z07:00"))
+
+	for _, value := range []string{f.hostname, f.appName, f.procID, ""} {
+		builder.WriteString(" ")
+		builder.WriteString(syslogHeaderValue(value))
+	}
+
+	builder.WriteString(" ")
+	builder.WriteString(f.structuredData(rec.Fields))
+
+	if rec.Message != "" {
+		builder.WriteString(" ")
+		builder.WriteString(rec.Message)
+	}
+
+	return builder.String(), nil
+}
+
+// structuredData returns the structured data element holding the fields, or "-" if there are none.
+func (f *RFC5424Formater) structuredData(fields []record.Field) string {
+	if len(fields) == 0 {
+		return "-"
+	}
+
+	var builder strings.Builder
+
+	builder.WriteString("[" + f.structuredDataID)
+
+	for _, field := range fields {
+		builder.WriteString(" " + structuredDataName(field.Key) + `="`)
+		builder.WriteString(structuredDataEscaper.Replace(fmt.Sprint(field.Value)))
+		builder.WriteString(`"`)
+	}
+
+	builder.WriteString("]")
+
+	return builder.String()
+}
+
+// structuredDataEscaper escapes the characters that must be escaped in structured data values.
+var structuredDataEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, `]`, `\]`)
+
+// structuredDataName returns a valid SD-NAME: at most 32 printable ASCII characters except '=', ' ', ']' and '"'.
+func structuredDataName(name string) string {
+	const maxLength = 32
+
+	sanitized := strings.Map(func(char rune) rune {
+		if char <= ' ' || char > '~' || char == '=' || char == ']' || char == '"' {
+			return '_'
+		}
+
+		return char
+	}, name)
+
+	if len(sanitized) > maxLength {
+		sanitized = sanitized[:maxLength]
+	}
+
+	return sanitized
+}
+
+// syslogHeaderValue returns the value, with spaces replaced, or "-" if it is empty.
+func syslogHeaderValue(value string) string {
+	if value == "" {
+		return "-"
+	}
+
+	return strings.ReplaceAll(value, " ", "_")
+}
+
+// RFC3164Formater is a formater that formats records as RFC 3164 (BSD) syslog messages.
+// The fields of the record are appended to the message as `key=value`.
+type RFC3164Formater struct {
+	syslogFormater
+}
+
+// NewRFC3164Formater creates a new RFC3164Formater.
+func NewRFC3164Formater() *RFC3164Formater {
+	return &RFC3164Formater{
+		syslogFormater: newSyslogFormater(),
+	}
+}
+
+// Format formats the message as an RFC 3164 syslog message.
+func (f *RFC3164Formater) Format(level levels.Level, message string) (string, error) {
+	return f.FormatRecord(record.New(level, message))
+}
+
+// FormatRecord formats the record as an RFC 3164 syslog message: `<PRI>TIMESTAMP HOSTNAME TAG[PID]: MSG`.
+func (f *RFC3164Formater) FormatRecord(rec record.Record) (string, error) {
+	var builder strings.Builder
+
+	builder.WriteString(f.priority(rec.Level))
+	builder.WriteString(rec.Time.Format("Jan _2 15:04:05"))
+	builder.WriteString(" " + syslogHeaderValue(f.hostname))
+	builder.WriteString(" " + syslogHeaderValue(f.appName))
+
+	if f.procID != "" {
+		builder.WriteString("[" + f.procID + "]")
+	}
+
+	builder.WriteString(": ")
+	builder.WriteString(rec.Message)
+
+	for _, field := range rec.Fields {
+		builder.WriteString(" " + field.Key + "=" + fmt.Sprint(field.Value))
+	}
+
+	return builder.String(), nil
+}
@@ -0,0 +1,235 @@
+package handler
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/ZertyCraft/GoLogger/levels"
+	"github.com/ZertyCraft/GoLogger/record"
+)
+
+// `OverflowPolicy` defines what the `AsyncHandler` does when its queue is full.
+type OverflowPolicy int
+
+const (
+	// `OverflowBlock` blocks the caller until there is room in the queue.
+	OverflowBlock OverflowPolicy = iota
+	// `OverflowDropNewest` drops the message being logged.
+	OverflowDropNewest
+	// `OverflowDropOldest` drops the oldest message of the queue to make room for the new one.
+	OverflowDropOldest
+	// `OverflowDropBelowLevel` drops the message being logged if its level is below the drop level,
+	// and blocks the caller otherwise.
+	OverflowDropBelowLevel
+)
+
+// `asyncRecord` is a message waiting in the queue of the `AsyncHandler`.
+// If `flushed` is set, the record is a flush request and the result is sent on it.
+type asyncRecord struct {
+	record  record.Record
+	flushed chan error
+}
+
+// `AsyncHandler` is a handler that logs messages with another handler in a background goroutine.
+// Messages are pushed into a bounded queue, so logging does not wait for slow handlers
+// (e.g. a `StreamHandler` writing to a slow disk).
+type AsyncHandler struct {
+	BaseHandler
+	target         Handler
+	queue          chan asyncRecord
+	overflowPolicy OverflowPolicy
+	dropLevel      levels.Level
+	dropped        atomic.Uint64
+	closed         bool
+	mutex          sync.RWMutex // Guards `closed` and sends on `queue` against Close
+	done           chan struct{}
+}
+
+const (
+	// `defaultOverflowPolicy` is the default value for the `overflowPolicy` field of the `AsyncHandler`.
+	defaultOverflowPolicy = OverflowBlock
+	// `defaultDropLevel` is the default value for the `dropLevel` field of the `AsyncHandler`.
+	defaultDropLevel = levels.WARN
+)
+
+// `NewAsyncHandler` returns a new `AsyncHandler` logging messages with `target`,
+// with a queue of `queueSize` messages. Sizes below 1 are set to 1.
+// The level of the `AsyncHandler` is DEBUG, so that filtering is left to the target.
+func NewAsyncHandler(target Handler, queueSize int) *AsyncHandler {
+	handler := &AsyncHandler{
+		BaseHandler:    *NewBaseHandler(),
+		target:         target,
+		queue:          make(chan asyncRecord, max(queueSize, 1)),
+		overflowPolicy: defaultOverflowPolicy,
+		dropLevel:      defaultDropLevel,
+		dropped:        atomic.Uint64{},
+		closed:         false,
+		mutex:          sync.RWMutex{},
+		done:           make(chan struct{}),
+	}
+	handler.Level = levels.DEBUG
+
+	go handler.run()
+
+	return handler
+}
+
+// ======== Setters ========
+// `SetOverflowPolicy` sets the value of the `overflowPolicy` field of the `AsyncHandler`.
+func (handler *AsyncHandler) SetOverflowPolicy(overflowPolicy OverflowPolicy) {
+	handler.overflowPolicy = overflowPolicy
+}
+
+// `SetDropLevel` sets the level below which messages are dropped with `OverflowDropBelowLevel`.
+func (handler *AsyncHandler) SetDropLevel(dropLevel levels.Level) {
+	handler.dropLevel = dropLevel
+}
+
+// ======== Getters ========
+// `GetOverflowPolicy` returns the value of the `overflowPolicy` field of the `AsyncHandler`.
+func (handler *AsyncHandler) GetOverflowPolicy() OverflowPolicy {
+	return handler.overflowPolicy
+}
+
+// `GetDropLevel` returns the value of the `dropLevel` field of the `AsyncHandler`.
+func (handler *AsyncHandler) GetDropLevel() levels.Level {
+	return handler.dropLevel
+}
+
+// `GetTarget` returns the handler the messages are logged with.
+func (handler *AsyncHandler) GetTarget() Handler {
+	return handler.target
+}
+
+// `Dropped` returns the number of messages dropped because the queue was full.
+func (handler *AsyncHandler) Dropped() uint64 {
+	return handler.dropped.Load()
+}
+
+// `Queued` returns the number of messages waiting in the queue.
+func (handler *AsyncHandler) Queued() int {
+	return len(handler.queue)
+}
+
+// ======== Methods ========
+// `run` logs the queued messages with the target until the queue is closed.
+func (handler *AsyncHandler) run() {
+	defer close(handler.done)
+
+	for queued := range handler.queue {
+		if queued.flushed != nil {
+			queued.flushed <- flushHandler(handler.target)
+
+			continue
+		}
+
+		LogRecord(handler.target, queued.record)
+	}
+}
+
+// `Log` pushes the message into the queue, applying the overflow policy if the queue is full.
+func (handler *AsyncHandler) Log(level levels.Level, message string) {
+	handler.LogRecord(record.New(level, message))
+}
+
+// `LogRecord` pushes the record into the queue, applying the overflow policy if the queue is full.
+func (handler *AsyncHandler) LogRecord(rec record.Record) {
+	if !handler.shouldLog(rec) {
+		return
+	}
+
+	handler.mutex.RLock()
+	defer handler.mutex.RUnlock()
+
+	if handler.closed {
+		handler.handleError(ErrClosed)
+
+		return
+	}
+
+	queued := asyncRecord{record: rec, flushed: nil}
+
+	switch handler.overflowPolicy {
+	case OverflowBlock:
+		handler.queue <- queued
+	case OverflowDropNewest:
+		handler.pushOrDrop(queued)
+	case OverflowDropOldest:
+		handler.pushDroppingOldest(queued)
+	case OverflowDropBelowLevel:
+		if rec.Level >= handler.dropLevel {
+			handler.queue <- queued
+		} else {
+			handler.pushOrDrop(queued)
+		}
+	}
+}
+
+// `pushOrDrop` pushes the record into the queue, or drops it if the queue is full.
+func (handler *AsyncHandler) pushOrDrop(record asyncRecord) {
+	select {
+	case handler.queue <- record:
+	default:
+		handler.dropped.Add(1)
+	}
+}
+
+// `pushDroppingOldest` pushes the record into the queue, dropping the oldest records until there is room.
+// Flush requests are never dropped: they are pushed back at the end of the queue.
+func (handler *AsyncHandler) pushDroppingOldest(record asyncRecord) {
+	for {
+		select {
+		case handler.queue <- record:
+			return
+		default:
+		}
+
+		select {
+		case oldest := <-handler.queue:
+			if oldest.flushed != nil {
+				handler.queue <- oldest
+			} else {
+				handler.dropped.Add(1)
+			}
+		default:
+		}
+	}
+}
+
+// `Flush` waits for the messages queued before the call to be logged, then flushes the target.
+func (handler *AsyncHandler) Flush() error {
+	handler.mutex.RLock()
+
+	if handler.closed {
+		handler.mutex.RUnlock()
+
+		return ErrClosed
+	}
+
+	flushed := make(chan error, 1)
+	handler.queue <- asyncRecord{record: record.Record{}, flushed: flushed}
+	handler.mutex.RUnlock()
+
+	return <-flushed
+}
+
+// `Close` stops accepting messages, waits for the queued messages to be logged,
+// then closes the target if it implements `Closer`, or flushes it if it implements `Flusher`.
+// Closing an already closed handler does nothing.
+func (handler *AsyncHandler) Close() error {
+	handler.mutex.Lock()
+
+	if handler.closed {
+		handler.mutex.Unlock()
+
+		return nil
+	}
+
+	handler.closed = true
+	close(handler.queue)
+	handler.mutex.Unlock()
+
+	<-handler.done
+
+	return closeHandler(handler.target)
+}
@@ -1,10 +1,12 @@
 package handler
 
 import (
+	"errors"
 	"log"
 
 	"github.com/ZertyCraft/GoLogger/formater"
 	"github.com/ZertyCraft/GoLogger/levels"
+	"github.com/ZertyCraft/GoLogger/record"
 )
 
 // Handler is an interface that defines the behavior of a log handler.
@@ -12,12 +14,65 @@ type Handler interface {
 	Log(level levels.Level, message string)
 }
 
+// RecordHandler is an optional interface implemented by handlers that use the whole record
+// (time, logger name, fields) instead of only the level and the message.
+type RecordHandler interface {
+	Handler
+	LogRecord(rec record.Record)
+}
+
+// LogRecord logs the record with the given handler,
+// using `LogRecord` if the handler implements `RecordHandler`, and `Log` otherwise.
+func LogRecord(handler Handler, rec record.Record) {
+	if recordHandler, ok := handler.(RecordHandler); ok {
+		recordHandler.LogRecord(rec)
+
+		return
+	}
+
+	handler.Log(rec.Level, rec.Message)
+}
+
+// Flusher is an optional interface implemented by handlers that buffer messages.
+type Flusher interface {
+	Flush() error
+}
+
+// Closer is an optional interface implemented by handlers that hold resources.
+// Once closed, a handler must not write messages anymore.
+type Closer interface {
+	Close() error
+}
+
+// ErrClosed is returned when a closed handler is used.
+var ErrClosed = errors.New("handler is closed")
+
+// `flushHandler` flushes the given handler if it implements `Flusher`.
+func flushHandler(handler Handler) error {
+	if flusher, ok := handler.(Flusher); ok {
+		return flusher.Flush()
+	}
+
+	return nil
+}
+
+// `closeHandler` closes the given handler if it implements `Closer`, or flushes it otherwise.
+func closeHandler(handler Handler) error {
+	if closer, ok := handler.(Closer); ok {
+		return closer.Close()
+	}
+
+	return flushHandler(handler)
+}
+
 // BaseHandler is a struct that implements the Handler interface.
 type BaseHandler struct {
 	Handler  // Embed the Handler interface
 	formater formater.Formater
 
-	Level levels.Level
+	Level        levels.Level
+	errorHandler func(error)
+	filters      []Filter
 }
 
 // `NewBaseHandler` creates a new instance of BaseHandler.
@@ -26,6 +81,9 @@ func NewBaseHandler() *BaseHandler {
 		Handler:  nil,
 		formater: formater.NewBaseFormater(""),
 		Level:    levels.INFO,
+
+		errorHandler: nil,
+		filters:      nil,
 	}
 }
 
@@ -49,6 +107,33 @@ func (h *BaseHandler) SetFormater(formater formater.Formater) {
 	h.formater = formater
 }
 
+// `AddFilter` adds a filter to the handler. A record is logged only if all filters allow it.
+func (h *BaseHandler) AddFilter(filter Filter) {
+	h.filters = append(h.filters, filter)
+}
+
+// `GetFilters` returns the filters of the handler.
+func (h *BaseHandler) GetFilters() []Filter {
+	return h.filters
+}
+
+// `SetErrorHandler` sets the callback called when the handler fails to write a message.
+// If no callback is set, errors are printed with the standard `log` package.
+func (h *BaseHandler) SetErrorHandler(errorHandler func(error)) {
+	h.errorHandler = errorHandler
+}
+
+// `handleError` reports the given error to the error handler of the handler.
+func (h *BaseHandler) handleError(err error) {
+	if h.errorHandler != nil {
+		h.errorHandler(err)
+
+		return
+	}
+
+	log.Println(err)
+}
+
 // `isLevelSufficient` checks if the given level is sufficient to be logged.
 // isLevelSufficient checks if the given log level is sufficient based on the handler's level.
 // It returns true if the log level is greater than or equal to the handler's level, otherwise false.
@@ -56,6 +141,22 @@ func (h *BaseHandler) isLevelSufficient(level levels.Level) bool {
 	return level >= h.Level
 }
 
+// `isAllowed` checks if all the filters of the handler allow the given record.
+func (h *BaseHandler) isAllowed(rec record.Record) bool {
+	for _, filter := range h.filters {
+		if !filter.Allow(rec) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// `shouldLog` checks if the given record has a sufficient level and is allowed by the filters.
+func (h *BaseHandler) shouldLog(rec record.Record) bool {
+	return h.isLevelSufficient(rec.Level) && h.isAllowed(rec)
+}
+
 // `Log` logs the given message using the handler (not implemented in BaseHandler).
 func (h *BaseHandler) Log(_ levels.Level, _ string) {
 	log.Fatal("`Log` method not implemented in `BaseHandler`")
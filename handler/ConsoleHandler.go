@@ -5,7 +5,9 @@ import (
 	"log"
 	"os"
 
+	"github.com/ZertyCraft/GoLogger/formater"
 	"github.com/ZertyCraft/GoLogger/levels"
+	"github.com/ZertyCraft/GoLogger/record"
 )
 
 // ConsoleHandler is a struct that represents a console log handler.
@@ -31,8 +33,13 @@ func NewConsoleHandler(writer ...io.Writer) *ConsoleHandler {
 
 // Log logs the given message using the console logger.
 func (h *ConsoleHandler) Log(level levels.Level, message string) {
-	if level >= h.Level {
-		formatedMessage, err := h.formater.Format(level, message)
+	h.LogRecord(record.New(level, message))
+}
+
+// LogRecord logs the given record using the console logger.
+func (h *ConsoleHandler) LogRecord(rec record.Record) {
+	if h.shouldLog(rec) {
+		formatedMessage, err := formater.FormatRecord(h.formater, rec)
 		if err != nil {
 			panic(err)
 		}
@@ -0,0 +1,162 @@
+package handler
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/ZertyCraft/GoLogger/levels"
+	"github.com/ZertyCraft/GoLogger/record"
+)
+
+// `DedupHandler` is a handler that collapses consecutive identical records logged with another handler,
+// like syslog: the first record is logged, the next identical ones are counted,
+// and "last message repeated N times" is logged when a different record arrives, after a timeout, or on flush.
+// Records are identical if they have the same level, message, logger name and fields.
+type DedupHandler struct {
+	BaseHandler
+	target   Handler
+	timeout  time.Duration // The delay after the last repeat before the repeat count is logged
+	last     record.Record // The last record logged
+	hasLast  bool          // Whether a record was logged
+	repeated int           // The number of repeats of the last record not logged yet
+	timer    *time.Timer
+	mutex    sync.Mutex
+}
+
+// `defaultDedupTimeout` is the default value for the `timeout` field of the `DedupHandler`.
+const defaultDedupTimeout = 30 * time.Second
+
+// `NewDedupHandler` returns a new `DedupHandler` logging with `target`.
+// The level of the `DedupHandler` is DEBUG, so that filtering is left to the target.
+func NewDedupHandler(target Handler) *DedupHandler {
+	handler := &DedupHandler{
+		BaseHandler: *NewBaseHandler(),
+		target:      target,
+		timeout:     defaultDedupTimeout,
+		last:        record.Record{},
+		hasLast:     false,
+		repeated:    0,
+		timer:       nil,
+		mutex:       sync.Mutex{},
+	}
+	handler.Level = levels.DEBUG
+
+	return handler
+}
+
+// ======== Setters ========
+// `SetTimeout` sets the delay after the last repeat before the repeat count is logged.
+func (handler *DedupHandler) SetTimeout(timeout time.Duration) {
+	handler.mutex.Lock()
+	defer handler.mutex.Unlock()
+
+	handler.timeout = timeout
+}
+
+// ======== Getters ========
+// `GetTimeout` returns the value of the `timeout` field of the `DedupHandler`.
+func (handler *DedupHandler) GetTimeout() time.Duration {
+	handler.mutex.Lock()
+	defer handler.mutex.Unlock()
+
+	return handler.timeout
+}
+
+// `GetTarget` returns the handler the records are logged with.
+func (handler *DedupHandler) GetTarget() Handler {
+	return handler.target
+}
+
+// ======== Methods ========
+// `Log` logs the message with the target, unless it repeats the last one.
+func (handler *DedupHandler) Log(level levels.Level, message string) {
+	handler.LogRecord(record.New(level, message))
+}
+
+// `LogRecord` logs the record with the target, unless it repeats the last one.
+func (handler *DedupHandler) LogRecord(rec record.Record) {
+	if !handler.shouldLog(rec) {
+		return
+	}
+
+	handler.mutex.Lock()
+	defer handler.mutex.Unlock()
+
+	if handler.hasLast && isSameRecord(handler.last, rec) {
+		handler.repeated++
+		handler.resetTimer()
+
+		return
+	}
+
+	handler.logRepeated()
+
+	handler.last = rec
+	handler.hasLast = true
+
+	LogRecord(handler.target, rec)
+}
+
+// `isSameRecord` checks if two records are identical, ignoring their time.
+func isSameRecord(first record.Record, second record.Record) bool {
+	return first.Level == second.Level &&
+		first.Message == second.Message &&
+		first.LoggerName == second.LoggerName &&
+		reflect.DeepEqual(first.Fields, second.Fields)
+}
+
+// `resetTimer` starts or restarts the timer logging the repeat count after the timeout.
+func (handler *DedupHandler) resetTimer() {
+	if handler.timer != nil {
+		handler.timer.Stop()
+	}
+
+	handler.timer = time.AfterFunc(handler.timeout, func() {
+		handler.mutex.Lock()
+		defer handler.mutex.Unlock()
+
+		handler.logRepeated()
+	})
+}
+
+// `logRepeated` logs the repeat count of the last record, if any, without acquiring the lock.
+// The next identical record is then counted from zero again.
+func (handler *DedupHandler) logRepeated() {
+	if handler.timer != nil {
+		handler.timer.Stop()
+		handler.timer = nil
+	}
+
+	if handler.repeated == 0 {
+		return
+	}
+
+	summary := record.New(handler.last.Level, fmt.Sprintf("last message repeated %d times", handler.repeated))
+	summary.LoggerName = handler.last.LoggerName
+	summary.Fields = []record.Field{{Key: "repeated", Value: handler.repeated}}
+
+	handler.repeated = 0
+
+	LogRecord(handler.target, summary)
+}
+
+// `Flush` logs the repeat count of the last record, if any, then flushes the target if it implements `Flusher`.
+func (handler *DedupHandler) Flush() error {
+	handler.mutex.Lock()
+	handler.logRepeated()
+	handler.mutex.Unlock()
+
+	return flushHandler(handler.target)
+}
+
+// `Close` logs the repeat count of the last record, if any,
+// then closes the target if it implements `Closer`, or flushes it otherwise.
+func (handler *DedupHandler) Close() error {
+	handler.mutex.Lock()
+	handler.logRepeated()
+	handler.mutex.Unlock()
+
+	return closeHandler(handler.target)
+}
@@ -0,0 +1,284 @@
+package handler
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// `deliveryQueue` is the bounded queue of the handlers sending items with a background worker
+// (`NetworkHandler`, `HTTPHandler`). It applies the overflow policy, starts the worker with the first item,
+// tracks the items being sent, and lets `Flush` and `Close` wait for them.
+type deliveryQueue[T any] struct {
+	items     []T
+	size      int  // The maximum number of queued items, at least 1
+	inFlight  int  // The number of items taken by the worker and not sent yet
+	flushing  bool // Whether the worker sends the queued items without waiting for them to be ready
+	closed    bool
+	dropped   atomic.Uint64
+	mutex     sync.Mutex
+	room      *sync.Cond    // Signals the callers blocked by `OverflowBlock` that items were taken from the queue
+	wakeUp    chan struct{} // Signals the worker that an item was queued or a flush was requested
+	progress  chan struct{} // Signals `flush` that the worker made progress
+	stop      chan struct{}
+	done      chan struct{}
+	startOnce sync.Once
+	worker    func() // Sends the queued items until the queue is stopped
+}
+
+// `newDeliveryQueue` returns a new `deliveryQueue` of `size` items, sent by `worker` once the first item is queued.
+func newDeliveryQueue[T any](size int, worker func()) *deliveryQueue[T] {
+	queue := &deliveryQueue[T]{
+		items:     make([]T, 0),
+		size:      max(size, 1),
+		inFlight:  0,
+		flushing:  false,
+		closed:    false,
+		dropped:   atomic.Uint64{},
+		mutex:     sync.Mutex{},
+		room:      nil,
+		wakeUp:    make(chan struct{}, 1),
+		progress:  make(chan struct{}, 1),
+		stop:      make(chan struct{}),
+		done:      make(chan struct{}),
+		startOnce: sync.Once{},
+		worker:    worker,
+	}
+	queue.room = sync.NewCond(&queue.mutex)
+
+	return queue
+}
+
+// `setSize` sets the maximum number of queued items. Sizes below 1 are set to 1,
+// so that the item being queued always fits in the queue.
+func (queue *deliveryQueue[T]) setSize(size int) {
+	queue.mutex.Lock()
+	defer queue.mutex.Unlock()
+
+	queue.size = max(size, 1)
+}
+
+// `length` returns the number of queued items.
+func (queue *deliveryQueue[T]) length() int {
+	queue.mutex.Lock()
+	defer queue.mutex.Unlock()
+
+	return len(queue.items)
+}
+
+// `start` starts the worker, if not started yet.
+func (queue *deliveryQueue[T]) start() {
+	queue.startOnce.Do(func() {
+		go func() {
+			defer close(queue.done)
+
+			queue.worker()
+		}()
+	})
+}
+
+// `push` appends the item to the queue, applying the overflow policy if the queue is full.
+// `OverflowDropBelowLevel` must be resolved by the caller into `OverflowBlock` or `OverflowDropNewest`.
+// It returns `ErrClosed` if the queue is closed.
+func (queue *deliveryQueue[T]) push(item T, policy OverflowPolicy) error {
+	// The worker must be running for a blocked caller to get room
+	queue.start()
+
+	queue.mutex.Lock()
+
+	for !queue.closed && len(queue.items) >= queue.size {
+		if policy == OverflowDropNewest {
+			queue.mutex.Unlock()
+			queue.dropped.Add(1)
+
+			return nil
+		}
+
+		if policy == OverflowDropOldest {
+			queue.items = queue.items[1:]
+			queue.dropped.Add(1)
+
+			break
+		}
+
+		notify(queue.wakeUp)
+		queue.room.Wait()
+	}
+
+	if queue.closed {
+		queue.mutex.Unlock()
+
+		return ErrClosed
+	}
+
+	queue.items = append(queue.items, item)
+	queue.mutex.Unlock()
+
+	notify(queue.wakeUp)
+
+	return nil
+}
+
+// `take` removes the next items from the queue, waiting for them to be ready. `ready` is called with the queued items
+// (never empty) and whether a flush is requested, and returns how many items to take, or how long to wait if none.
+// It returns false when the queue is stopped.
+func (queue *deliveryQueue[T]) take(ready func(items []T, flushing bool) (int, time.Duration)) ([]T, bool) {
+	for {
+		queue.mutex.Lock()
+
+		var wait time.Duration
+
+		if len(queue.items) > 0 {
+			var count int
+
+			count, wait = ready(queue.items, queue.flushing)
+			if count > 0 {
+				items := queue.items[:count:count]
+				queue.items = queue.items[count:]
+				queue.inFlight = count
+
+				if len(queue.items) == 0 {
+					queue.flushing = false
+				}
+
+				queue.room.Broadcast()
+				queue.mutex.Unlock()
+
+				return items, true
+			}
+		}
+
+		queue.mutex.Unlock()
+
+		if wait <= 0 {
+			select {
+			case <-queue.stop:
+				return nil, false
+			case <-queue.wakeUp:
+			}
+
+			continue
+		}
+
+		timer := time.NewTimer(wait)
+
+		select {
+		case <-queue.stop:
+			timer.Stop()
+
+			return nil, false
+		case <-queue.wakeUp:
+		case <-timer.C:
+		}
+
+		timer.Stop()
+	}
+}
+
+// `requeue` puts back an item that could not be sent at the front of the queue, dropping it if the queue is full.
+func (queue *deliveryQueue[T]) requeue(item T) {
+	queue.mutex.Lock()
+	defer queue.mutex.Unlock()
+
+	queue.inFlight = 0
+
+	if len(queue.items) >= queue.size {
+		queue.dropped.Add(1)
+
+		return
+	}
+
+	queue.items = append([]T{item}, queue.items...)
+}
+
+// `sent` marks the items taken from the queue as sent, or given up.
+func (queue *deliveryQueue[T]) sent() {
+	queue.mutex.Lock()
+	queue.inFlight = 0
+	queue.mutex.Unlock()
+
+	notify(queue.progress)
+}
+
+// `sleep` waits for the given duration, returning false if the queue is stopped meanwhile.
+func (queue *deliveryQueue[T]) sleep(duration time.Duration) bool {
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+
+	select {
+	case <-queue.stop:
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+// `pending` returns the number of items not sent yet.
+func (queue *deliveryQueue[T]) pending() int {
+	queue.mutex.Lock()
+	defer queue.mutex.Unlock()
+
+	return len(queue.items) + queue.inFlight
+}
+
+// `flush` asks the worker to send the queued items without waiting for them to be ready, and waits for them
+// to be sent, at most for `timeout`, checking every `poll`. It returns the number of items not sent.
+func (queue *deliveryQueue[T]) flush(timeout time.Duration, poll time.Duration) int {
+	queue.mutex.Lock()
+	queue.flushing = len(queue.items) > 0
+	queue.mutex.Unlock()
+
+	notify(queue.wakeUp)
+
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+
+	for {
+		pending := queue.pending()
+		if pending == 0 {
+			return 0
+		}
+
+		select {
+		case <-queue.progress:
+		case <-deadline.C:
+			return pending
+		case <-time.After(poll):
+			// Check again, in case the progress signal was consumed by another caller
+		}
+	}
+}
+
+// `close` stops accepting items, flushes the queue like `flush`, then stops the worker.
+// It returns the number of items not sent, and false if the queue was already closed.
+func (queue *deliveryQueue[T]) close(timeout time.Duration, poll time.Duration) (int, bool) {
+	queue.mutex.Lock()
+
+	if queue.closed {
+		queue.mutex.Unlock()
+
+		return 0, false
+	}
+
+	queue.closed = true
+	queue.room.Broadcast()
+	queue.mutex.Unlock()
+
+	pending := queue.flush(timeout, poll)
+
+	// Start the worker if no item was queued, so that `done` is closed
+	queue.start()
+
+	close(queue.stop)
+	<-queue.done
+
+	return pending, true
+}
+
+// `notify` signals the channel without blocking.
+func notify(channel chan struct{}) {
+	select {
+	case channel <- struct{}{}:
+	default:
+	}
+}
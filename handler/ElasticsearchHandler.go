@@ -0,0 +1,156 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// `ElasticsearchHandler` is a handler that indexes records in Elasticsearch or OpenSearch with the `_bulk` API.
+// It is an `HTTPHandler` sending each batch as one bulk request, in indices named after the date of the records
+// (e.g. `logs-2026.10.18`). The documents are formatted with the formater of the handler (a `JSONFormater` by default).
+// When the bulk response reports failed items, only the items rejected with a 429 or 5xx status are retried,
+// and the other ones are reported to the error handler with `ErrDeliveryFailed`.
+// To apply backpressure when the cluster is slow, logging blocks while the queue is full (`OverflowBlock`).
+type ElasticsearchHandler struct {
+	*HTTPHandler
+	indexPrefix string // The prefix of the index names
+	indexLayout string // The layout of the date in the index names (see `time.Layout`), no date if empty
+}
+
+const (
+	// `defaultIndexPrefix` is the default value for the `indexPrefix` field of the `ElasticsearchHandler`.
+	defaultIndexPrefix = "logs-"
+	// `defaultIndexLayout` is the default value for the `indexLayout` field of the `ElasticsearchHandler`.
+	defaultIndexLayout = "2006.01.02"
+)
+
+// `bulkResponse` is the part of a `_bulk` response telling which items failed.
+type bulkResponse struct {
+	Errors bool `json:"errors"`
+	Items  []map[string]struct {
+		Status int             `json:"status"`
+		Error  json.RawMessage `json:"error"`
+	} `json:"items"`
+}
+
+// `NewElasticsearchHandler` returns a new `ElasticsearchHandler` sending bulk requests to the cluster at `url`
+// (e.g. "http://localhost:9200"), in daily indices named `logs-2006.01.02` after the UTC date of the records.
+func NewElasticsearchHandler(url string) *ElasticsearchHandler {
+	handler := &ElasticsearchHandler{
+		HTTPHandler: NewHTTPHandler(strings.TrimSuffix(url, "/") + "/_bulk"),
+		indexPrefix: defaultIndexPrefix,
+		indexLayout: defaultIndexLayout,
+	}
+	handler.encoding = HTTPEncodingNDJSON
+	handler.overflowPolicy = OverflowBlock
+	handler.encodeBatch = handler.bulkBody
+	handler.checkResponse = handler.checkBulkResponse
+
+	return handler
+}
+
+// ======== Setters ========
+// `SetIndex` sets the prefix of the index names and the layout of the date following it (see `time.Layout`),
+// e.g. "logs-" and "2006.01". The index name is only the prefix if the layout is empty.
+func (handler *ElasticsearchHandler) SetIndex(prefix string, layout string) {
+	handler.indexPrefix = prefix
+	handler.indexLayout = layout
+}
+
+// ======== Getters ========
+// `GetIndexPrefix` returns the value of the `indexPrefix` field of the `ElasticsearchHandler`.
+func (handler *ElasticsearchHandler) GetIndexPrefix() string {
+	return handler.indexPrefix
+}
+
+// `GetIndexLayout` returns the value of the `indexLayout` field of the `ElasticsearchHandler`.
+func (handler *ElasticsearchHandler) GetIndexLayout() string {
+	return handler.indexLayout
+}
+
+// ======== Methods ========
+// `index` returns the name of the index of the item.
+func (handler *ElasticsearchHandler) index(item httpItem) string {
+	if handler.indexLayout == "" {
+		return handler.indexPrefix
+	}
+
+	return handler.indexPrefix + item.rec.Time.UTC().Format(handler.indexLayout)
+}
+
+// `bulkBody` returns the bulk request of the batch: an `index` action followed by the document for each record.
+func (handler *ElasticsearchHandler) bulkBody(items []httpItem) ([]byte, error) {
+	var buffer bytes.Buffer
+
+	for _, item := range items {
+		action, err := json.Marshal(map[string]map[string]string{"index": {"_index": handler.index(item)}})
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode action: %w", err)
+		}
+
+		buffer.Write(action)
+		buffer.WriteByte('\n')
+		buffer.Write(item.data)
+		buffer.WriteByte('\n')
+	}
+
+	return buffer.Bytes(), nil
+}
+
+// `checkBulkResponse` returns the items of the batch rejected with a 429 or 5xx status, to retry them,
+// and reports the items rejected with other statuses.
+func (handler *ElasticsearchHandler) checkBulkResponse(
+	response *http.Response, body []byte, items []httpItem,
+) ([]httpItem, error) {
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return checkHTTPStatus(response, body, items)
+	}
+
+	var bulk bulkResponse
+	if err := json.Unmarshal(body, &bulk); err != nil {
+		return nil, fmt.Errorf("failed to decode bulk response: %w", err)
+	}
+
+	if !bulk.Errors {
+		return nil, nil
+	}
+
+	retry := make([]httpItem, 0)
+	failed := 0
+
+	var retryError, failedError json.RawMessage
+
+	for i, result := range bulk.Items {
+		if i >= len(items) {
+			break
+		}
+
+		for _, action := range result {
+			if action.Status >= 200 && action.Status < 300 {
+				continue
+			}
+
+			if action.Status == http.StatusTooManyRequests || action.Status >= 500 {
+				retry = append(retry, items[i])
+				retryError = action.Error
+			} else {
+				failed++
+				failedError = action.Error
+			}
+		}
+	}
+
+	if failed > 0 {
+		handler.queue.dropped.Add(uint64(failed))
+		handler.handleError(fmt.Errorf("%w: %d records rejected by %s: %s", ErrDeliveryFailed, failed, handler.url, failedError))
+	}
+
+	if len(retry) > 0 {
+		return retry, fmt.Errorf("%d records rejected: %s", len(retry), retryError)
+	}
+
+	return nil, nil
+}
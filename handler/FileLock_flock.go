@@ -0,0 +1,27 @@
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd
+
+package handler
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+)
+
+// `lockFile` acquires an exclusive advisory lock on the given file, blocking until it is available.
+func lockFile(file *os.File) error {
+	if err := syscall.Flock(int(file.Fd()), syscall.LOCK_EX); err != nil {
+		return fmt.Errorf("failed to lock file: %w", err)
+	}
+
+	return nil
+}
+
+// `unlockFile` releases the advisory lock on the given file.
+func unlockFile(file *os.File) error {
+	if err := syscall.Flock(int(file.Fd()), syscall.LOCK_UN); err != nil {
+		return fmt.Errorf("failed to unlock file: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,21 @@
+//go:build !(darwin || dragonfly || freebsd || linux || netbsd || openbsd)
+
+package handler
+
+import (
+	"errors"
+	"os"
+)
+
+// `errFileLockNotSupported` is returned when advisory file locks are not available on the platform.
+var errFileLockNotSupported = errors.New("file locking is not supported on this platform")
+
+// `lockFile` is not supported on this platform.
+func lockFile(_ *os.File) error {
+	return errFileLockNotSupported
+}
+
+// `unlockFile` is not supported on this platform.
+func unlockFile(_ *os.File) error {
+	return errFileLockNotSupported
+}
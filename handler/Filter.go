@@ -0,0 +1,93 @@
+package handler
+
+import (
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/ZertyCraft/GoLogger/levels"
+	"github.com/ZertyCraft/GoLogger/record"
+)
+
+// Filter is an interface that decides whether a record is logged.
+type Filter interface {
+	Allow(rec record.Record) bool
+}
+
+// FilterFunc is a function implementing the Filter interface.
+type FilterFunc func(rec record.Record) bool
+
+// `Allow` calls the function.
+func (f FilterFunc) Allow(rec record.Record) bool {
+	return f(rec)
+}
+
+// `LevelRange` allows records with a level between `minLevel` and `maxLevel` (inclusive).
+func LevelRange(minLevel levels.Level, maxLevel levels.Level) Filter {
+	return FilterFunc(func(rec record.Record) bool {
+		return rec.Level >= minLevel && rec.Level <= maxLevel
+	})
+}
+
+// `MessageContains` allows records whose message contains `substring`.
+func MessageContains(substring string) Filter {
+	return FilterFunc(func(rec record.Record) bool {
+		return strings.Contains(rec.Message, substring)
+	})
+}
+
+// `MessageMatches` allows records whose message matches the regular expression.
+func MessageMatches(expression *regexp.Regexp) Filter {
+	return FilterFunc(func(rec record.Record) bool {
+		return expression.MatchString(rec.Message)
+	})
+}
+
+// `FieldEquals` allows records having a field `key` equal to `value`.
+func FieldEquals(key string, value any) Filter {
+	return FilterFunc(func(rec record.Record) bool {
+		fieldValue, ok := rec.Field(key)
+
+		return ok && reflect.DeepEqual(fieldValue, value)
+	})
+}
+
+// `LoggerNamePrefix` allows records whose logger name starts with `prefix`.
+func LoggerNamePrefix(prefix string) Filter {
+	return FilterFunc(func(rec record.Record) bool {
+		return strings.HasPrefix(rec.LoggerName, prefix)
+	})
+}
+
+// `And` allows records allowed by all the filters.
+func And(filters ...Filter) Filter {
+	return FilterFunc(func(rec record.Record) bool {
+		for _, filter := range filters {
+			if !filter.Allow(rec) {
+				return false
+			}
+		}
+
+		return true
+	})
+}
+
+// `Or` allows records allowed by at least one of the filters.
+func Or(filters ...Filter) Filter {
+	return FilterFunc(func(rec record.Record) bool {
+		for _, filter := range filters {
+			if filter.Allow(rec) {
+				return true
+			}
+		}
+
+		return false
+	})
+}
+
+// `Not` allows records rejected by the filter.
+func Not(filter Filter) Filter {
+	return FilterFunc(func(rec record.Record) bool {
+		return !filter.Allow(rec)
+	})
+}
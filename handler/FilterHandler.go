@@ -0,0 +1,54 @@
+package handler
+
+import (
+	"github.com/ZertyCraft/GoLogger/levels"
+	"github.com/ZertyCraft/GoLogger/record"
+)
+
+// `FilterHandler` is a handler that logs with another handler only the records allowed by its filters.
+type FilterHandler struct {
+	BaseHandler
+	target Handler
+}
+
+// `NewFilterHandler` returns a new `FilterHandler` logging with `target` the records allowed by all the filters.
+// The level of the `FilterHandler` is DEBUG, so that filtering by level is left to the target.
+func NewFilterHandler(target Handler, filters ...Filter) *FilterHandler {
+	handler := &FilterHandler{
+		BaseHandler: *NewBaseHandler(),
+		target:      target,
+	}
+	handler.Level = levels.DEBUG
+	handler.filters = append(handler.filters, filters...)
+
+	return handler
+}
+
+// `GetTarget` returns the handler the records are logged with.
+func (handler *FilterHandler) GetTarget() Handler {
+	return handler.target
+}
+
+// `Log` logs the message with the target if it is allowed by the filters.
+func (handler *FilterHandler) Log(level levels.Level, message string) {
+	handler.LogRecord(record.New(level, message))
+}
+
+// `LogRecord` logs the record with the target if it is allowed by the filters.
+func (handler *FilterHandler) LogRecord(rec record.Record) {
+	if !handler.shouldLog(rec) {
+		return
+	}
+
+	LogRecord(handler.target, rec)
+}
+
+// `Flush` flushes the target if it implements `Flusher`.
+func (handler *FilterHandler) Flush() error {
+	return flushHandler(handler.target)
+}
+
+// `Close` closes the target if it implements `Closer`, or flushes it otherwise.
+func (handler *FilterHandler) Close() error {
+	return closeHandler(handler.target)
+}
@@ -0,0 +1,162 @@
+package handler
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net"
+	"strings"
+
+	"github.com/ZertyCraft/GoLogger/formater"
+)
+
+// `GELFCompression` defines how the `GELFHandler` compresses messages sent over UDP.
+type GELFCompression int
+
+const (
+	// `GELFCompressionNone` sends the messages uncompressed.
+	GELFCompressionNone GELFCompression = iota
+	// `GELFCompressionGzip` compresses the messages with gzip.
+	GELFCompressionGzip
+	// `GELFCompressionZlib` compresses the messages with zlib.
+	GELFCompressionZlib
+)
+
+const (
+	// `GELFChunkSizeWAN` is the chunk size recommended when the messages cross networks.
+	GELFChunkSizeWAN = 1420
+	// `GELFChunkSizeLAN` is the chunk size recommended on local networks.
+	GELFChunkSizeLAN = 8154
+	// `gelfMaxChunks` is the maximum number of chunks of a message.
+	gelfMaxChunks = 128
+	// `gelfChunkHeaderSize` is the size of the header of a chunk: magic bytes, message id, sequence number and count.
+	gelfChunkHeaderSize = 12
+	// `gelfMinChunkSize` is the smallest chunk size, leaving room for one byte of payload after the header.
+	gelfMinChunkSize = gelfChunkHeaderSize + 1
+)
+
+// `errGELFMessageTooLarge` is returned when a message needs more than the maximum number of chunks.
+var errGELFMessageTooLarge = fmt.Errorf("%w: GELF message too large", errUnsendableMessage)
+
+// `GELFHandler` is a handler that sends records to Graylog in GELF, over UDP or TCP.
+// It is a `NetworkHandler` formatting records with a `GELFFormater` by default.
+// Over UDP, messages can be compressed, and messages larger than the chunk size are split into chunks.
+// Over TCP, messages are delimited with a null byte and are not compressed.
+type GELFHandler struct {
+	*NetworkHandler
+	compression GELFCompression // The compression of the messages sent over UDP
+	chunkSize   int             // The maximum size of a datagram sent over UDP
+}
+
+// `NewGELFHandler` returns a new `GELFHandler` sending messages to `address` over `network` ("udp" or "tcp"),
+// e.g. "udp" and "graylog:12201".
+func NewGELFHandler(network string, address string) *GELFHandler {
+	handler := &GELFHandler{
+		NetworkHandler: NewNetworkHandler(network, address),
+		compression:    GELFCompressionNone,
+		chunkSize:      GELFChunkSizeWAN,
+	}
+	handler.formater = formater.NewGELFFormater()
+	handler.framing = FramingNullByte
+
+	if strings.HasPrefix(network, "udp") {
+		handler.writeMessage = handler.writeChunked
+	}
+
+	return handler
+}
+
+// ======== Setters ========
+// `SetCompression` sets the compression of the messages sent over UDP.
+func (handler *GELFHandler) SetCompression(compression GELFCompression) {
+	handler.compression = compression
+}
+
+// `SetChunkSize` sets the maximum size of a datagram sent over UDP, e.g. `GELFChunkSizeLAN`.
+// Sizes too small to hold the chunk header and a byte of payload are set to `gelfMinChunkSize`.
+func (handler *GELFHandler) SetChunkSize(chunkSize int) {
+	handler.chunkSize = max(chunkSize, gelfMinChunkSize)
+}
+
+// ======== Getters ========
+// `GetCompression` returns the value of the `compression` field of the `GELFHandler`.
+func (handler *GELFHandler) GetCompression() GELFCompression {
+	return handler.compression
+}
+
+// `GetChunkSize` returns the value of the `chunkSize` field of the `GELFHandler`.
+func (handler *GELFHandler) GetChunkSize() int {
+	return handler.chunkSize
+}
+
+// ======== Methods ========
+// `writeChunked` compresses the message, then sends it in one datagram, or in chunks if it is larger than the chunk size.
+// Each chunk starts with the magic bytes 0x1e 0x0f, the message id, the sequence number and the number of chunks.
+func (handler *GELFHandler) writeChunked(conn net.Conn, message []byte) error {
+	data, err := handler.compress(message)
+	if err != nil {
+		return err
+	}
+
+	if len(data) <= handler.chunkSize {
+		if _, err := conn.Write(data); err != nil {
+			return fmt.Errorf("failed to write: %w", err)
+		}
+
+		return nil
+	}
+
+	payloadSize := handler.chunkSize - gelfChunkHeaderSize
+	count := (len(data) + payloadSize - 1) / payloadSize
+
+	if count > gelfMaxChunks {
+		return fmt.Errorf("%w: %d bytes in %d chunks of %d bytes", errGELFMessageTooLarge, len(data), count, handler.chunkSize)
+	}
+
+	messageID := rand.Uint64()
+
+	for sequence := 0; sequence < count; sequence++ {
+		chunk := make([]byte, gelfChunkHeaderSize, handler.chunkSize)
+		chunk[0], chunk[1] = 0x1e, 0x0f
+		binary.BigEndian.PutUint64(chunk[2:10], messageID)
+		chunk[10], chunk[11] = byte(sequence), byte(count)
+		chunk = append(chunk, data[sequence*payloadSize:min((sequence+1)*payloadSize, len(data))]...)
+
+		if _, err := conn.Write(chunk); err != nil {
+			return fmt.Errorf("failed to write chunk %d of %d: %w", sequence+1, count, err)
+		}
+	}
+
+	return nil
+}
+
+// `compress` returns the message compressed with the compression of the handler.
+func (handler *GELFHandler) compress(message []byte) ([]byte, error) {
+	var (
+		buffer bytes.Buffer
+		writer io.WriteCloser
+	)
+
+	switch handler.compression {
+	case GELFCompressionGzip:
+		writer = gzip.NewWriter(&buffer)
+	case GELFCompressionZlib:
+		writer = zlib.NewWriter(&buffer)
+	default:
+		return message, nil
+	}
+
+	if _, err := writer.Write(message); err != nil {
+		return nil, fmt.Errorf("failed to compress message: %w", err)
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, fmt.Errorf("failed to compress message: %w", err)
+	}
+
+	return buffer.Bytes(), nil
+}
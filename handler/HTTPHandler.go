@@ -0,0 +1,466 @@
+package handler
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ZertyCraft/GoLogger/formater"
+	"github.com/ZertyCraft/GoLogger/levels"
+	"github.com/ZertyCraft/GoLogger/record"
+)
+
+// `HTTPEncoding` defines how the `HTTPHandler` joins the formatted records of a batch in the request body.
+type HTTPEncoding int
+
+const (
+	// `HTTPEncodingJSONArray` sends the batch as a JSON array of the formatted records,
+	// which must be JSON values (e.g. formatted with a `JSONFormater`).
+	HTTPEncodingJSONArray HTTPEncoding = iota
+	// `HTTPEncodingNDJSON` sends the batch as newline-delimited formatted records.
+	HTTPEncodingNDJSON
+)
+
+// `ErrDeliveryFailed` is reported to the error handler when a batch could not be delivered.
+var ErrDeliveryFailed = errors.New("failed to deliver records")
+
+// `httpItem` is a record waiting in the queue of the `HTTPHandler`, with its encoded form.
+type httpItem struct {
+	rec    record.Record
+	data   []byte
+	queued time.Time // The time the record was queued, to send it after the flush interval
+	resent int       // The number of times the record was queued again after its delivery was not confirmed
+}
+
+// `HTTPHandler` is a handler that posts batches of formatted records to an HTTP endpoint.
+// Records are pushed into a bounded queue and sent by a background goroutine
+// when the batch reaches its maximum count or size, or when the oldest record has waited for the flush interval.
+// Batches failing with a network error, a 5xx or a 429 status are retried with an exponential backoff,
+// and the records of batches that cannot be delivered are reported to the error handler with `ErrDeliveryFailed`.
+type HTTPHandler struct {
+	BaseHandler
+	url            string
+	client         *http.Client
+	headers        http.Header
+	encoding       HTTPEncoding
+	gzip           bool           // Whether the request bodies are compressed
+	maxBatchCount  int            // The maximum number of records of a batch
+	maxBatchBytes  int            // The maximum size of the encoded records of a batch
+	flushInterval  time.Duration  // The maximum time a record waits before being sent
+	overflowPolicy OverflowPolicy // What to do with records logged when the queue is full
+	dropLevel      levels.Level   // The level below which records are dropped with `OverflowDropBelowLevel`
+	maxRetries     int            // The number of retries of a failed batch
+	minBackoff     time.Duration  // The delay before the first retry
+	maxBackoff     time.Duration  // The maximum delay between retries
+	flushTimeout   time.Duration  // The maximum time `Flush` and `Close` wait for the queue to be sent
+	// `encodeRecord` encodes a record when it is queued.
+	encodeRecord func(rec record.Record) ([]byte, error)
+	// `encodeBatch` returns the request body of a batch.
+	encodeBatch func(items []httpItem) ([]byte, error)
+	// `checkResponse` returns the records of the batch to retry and the error of the response, if any.
+	checkResponse func(response *http.Response, body []byte, items []httpItem) ([]httpItem, error)
+	queue         *deliveryQueue[httpItem]
+}
+
+const (
+	// `defaultHTTPMaxBatchCount` is the default value for the `maxBatchCount` field of the `HTTPHandler`.
+	defaultHTTPMaxBatchCount = 100
+	// `defaultHTTPMaxBatchBytes` is the default value for the `maxBatchBytes` field of the `HTTPHandler`.
+	defaultHTTPMaxBatchBytes = 1024 * 1024
+	// `defaultHTTPFlushInterval` is the default value for the `flushInterval` field of the `HTTPHandler`.
+	defaultHTTPFlushInterval = time.Second
+	// `defaultHTTPQueueSize` is the default value for the `queueSize` field of the `HTTPHandler`.
+	defaultHTTPQueueSize = 10000
+	// `defaultHTTPMaxRetries` is the default value for the `maxRetries` field of the `HTTPHandler`.
+	defaultHTTPMaxRetries = 5
+	// `defaultHTTPTimeout` is the default timeout of the requests of the `HTTPHandler`.
+	defaultHTTPTimeout = 10 * time.Second
+)
+
+// `NewHTTPHandler` returns a new `HTTPHandler` posting batches of records to `url` as JSON arrays.
+// The default formater is a `JSONFormater`, and the queue drops its oldest records when full.
+func NewHTTPHandler(url string) *HTTPHandler {
+	handler := &HTTPHandler{
+		BaseHandler:    *NewBaseHandler(),
+		url:            url,
+		client:         &http.Client{Timeout: defaultHTTPTimeout},
+		headers:        make(http.Header),
+		encoding:       HTTPEncodingJSONArray,
+		gzip:           false,
+		maxBatchCount:  defaultHTTPMaxBatchCount,
+		maxBatchBytes:  defaultHTTPMaxBatchBytes,
+		flushInterval:  defaultHTTPFlushInterval,
+		overflowPolicy: OverflowDropOldest,
+		dropLevel:      defaultDropLevel,
+		maxRetries:     defaultHTTPMaxRetries,
+		minBackoff:     defaultMinBackoff,
+		maxBackoff:     defaultMaxBackoff,
+		flushTimeout:   defaultFlushTimeout,
+		encodeRecord:   nil,
+		encodeBatch:    nil,
+		checkResponse:  checkHTTPStatus,
+		queue:          nil,
+	}
+	handler.formater = formater.NewJSONFormater()
+	handler.encodeRecord = handler.formatRecord
+	handler.encodeBatch = handler.joinRecords
+	handler.queue = newDeliveryQueue[httpItem](defaultHTTPQueueSize, handler.run)
+
+	return handler
+}
+
+// ======== Setters ========
+// `SetHeader` sets a header sent with every request, e.g. an authorization token.
+func (handler *HTTPHandler) SetHeader(key string, value string) {
+	handler.headers.Set(key, value)
+}
+
+// `SetClient` sets the HTTP client sending the requests, e.g. to configure TLS or the timeout.
+func (handler *HTTPHandler) SetClient(client *http.Client) {
+	handler.client = client
+}
+
+// `SetEncoding` sets the value of the `encoding` field of the `HTTPHandler`.
+func (handler *HTTPHandler) SetEncoding(encoding HTTPEncoding) {
+	handler.encoding = encoding
+}
+
+// `SetGzip` sets whether the request bodies are compressed with gzip.
+func (handler *HTTPHandler) SetGzip(gzip bool) {
+	handler.gzip = gzip
+}
+
+// `SetBatchSize` sets the maximum number of records and the maximum size in bytes of the encoded records of a batch.
+// Values below 1 are set to 1, so that a batch always holds at least one record.
+func (handler *HTTPHandler) SetBatchSize(maxCount int, maxBytes int) {
+	// The batch size is read by the worker while it holds the lock of the queue
+	handler.queue.mutex.Lock()
+	defer handler.queue.mutex.Unlock()
+
+	handler.maxBatchCount = max(maxCount, 1)
+	handler.maxBatchBytes = max(maxBytes, 1)
+}
+
+// `SetFlushInterval` sets the maximum time a record waits in the queue before being sent.
+func (handler *HTTPHandler) SetFlushInterval(flushInterval time.Duration) {
+	handler.queue.mutex.Lock()
+	defer handler.queue.mutex.Unlock()
+
+	handler.flushInterval = flushInterval
+}
+
+// `SetQueueSize` sets the maximum number of queued records.
+// Sizes below 1 are set to 1, so that the record being logged always fits in the queue.
+func (handler *HTTPHandler) SetQueueSize(queueSize int) {
+	handler.queue.setSize(queueSize)
+}
+
+// `SetOverflowPolicy` sets what to do with the records logged when the queue is full.
+func (handler *HTTPHandler) SetOverflowPolicy(overflowPolicy OverflowPolicy) {
+	handler.overflowPolicy = overflowPolicy
+}
+
+// `SetDropLevel` sets the level below which records are dropped with `OverflowDropBelowLevel`.
+func (handler *HTTPHandler) SetDropLevel(dropLevel levels.Level) {
+	handler.dropLevel = dropLevel
+}
+
+// `SetMaxRetries` sets the number of retries of a failed batch.
+func (handler *HTTPHandler) SetMaxRetries(maxRetries int) {
+	handler.maxRetries = maxRetries
+}
+
+// `SetBackoff` sets the delay before the first retry and the maximum delay between retries.
+func (handler *HTTPHandler) SetBackoff(minBackoff time.Duration, maxBackoff time.Duration) {
+	handler.minBackoff = minBackoff
+	handler.maxBackoff = maxBackoff
+}
+
+// `SetFlushTimeout` sets the maximum time `Flush` and `Close` wait for the queued records to be sent.
+func (handler *HTTPHandler) SetFlushTimeout(flushTimeout time.Duration) {
+	handler.flushTimeout = flushTimeout
+}
+
+// ======== Getters ========
+// `GetURL` returns the URL the records are posted to.
+func (handler *HTTPHandler) GetURL() string {
+	return handler.url
+}
+
+// `GetEncoding` returns the value of the `encoding` field of the `HTTPHandler`.
+func (handler *HTTPHandler) GetEncoding() HTTPEncoding {
+	return handler.encoding
+}
+
+// `GetOverflowPolicy` returns the value of the `overflowPolicy` field of the `HTTPHandler`.
+func (handler *HTTPHandler) GetOverflowPolicy() OverflowPolicy {
+	return handler.overflowPolicy
+}
+
+// `Dropped` returns the number of records dropped because the queue was full or their batch could not be delivered.
+func (handler *HTTPHandler) Dropped() uint64 {
+	return handler.queue.dropped.Load()
+}
+
+// `Queued` returns the number of records waiting to be sent.
+func (handler *HTTPHandler) Queued() int {
+	return handler.queue.length()
+}
+
+// ======== Methods ========
+// `Log` queues the message to be sent.
+func (handler *HTTPHandler) Log(level levels.Level, message string) {
+	handler.LogRecord(record.New(level, message))
+}
+
+// `LogRecord` encodes the record and queues it to be sent, applying the overflow policy if the queue is full.
+func (handler *HTTPHandler) LogRecord(rec record.Record) {
+	if !handler.shouldLog(rec) {
+		return
+	}
+
+	data, err := handler.encodeRecord(rec)
+	if err != nil {
+		handler.handleError(fmt.Errorf("failed to format message: %w", err))
+
+		return
+	}
+
+	handler.enqueue(httpItem{rec: rec, data: data, queued: time.Now(), resent: 0})
+}
+
+// `formatRecord` encodes the record with the formater of the handler.
+func (handler *HTTPHandler) formatRecord(rec record.Record) ([]byte, error) {
+	message, err := formater.FormatRecord(handler.formater, rec)
+	if err != nil {
+		return nil, err
+	}
+
+	return []byte(strings.TrimSuffix(message, "\n")), nil
+}
+
+// `joinRecords` joins the encoded records of the batch according to the encoding.
+func (handler *HTTPHandler) joinRecords(items []httpItem) ([]byte, error) {
+	var buffer bytes.Buffer
+
+	if handler.encoding == HTTPEncodingNDJSON {
+		for _, item := range items {
+			buffer.Write(item.data)
+			buffer.WriteByte('\n')
+		}
+
+		return buffer.Bytes(), nil
+	}
+
+	buffer.WriteByte('[')
+
+	for i, item := range items {
+		if i > 0 {
+			buffer.WriteByte(',')
+		}
+
+		buffer.Write(item.data)
+	}
+
+	buffer.WriteByte(']')
+
+	return buffer.Bytes(), nil
+}
+
+// `contentType` returns the content type of the request bodies.
+func (handler *HTTPHandler) contentType() string {
+	if handler.encoding == HTTPEncodingNDJSON {
+		return "application/x-ndjson"
+	}
+
+	return "application/json"
+}
+
+// `enqueue` pushes the item into the queue, applying the overflow policy if the queue is full.
+func (handler *HTTPHandler) enqueue(item httpItem) {
+	policy := handler.overflowPolicy
+	if policy == OverflowDropBelowLevel {
+		policy = OverflowBlock
+
+		if item.rec.Level < handler.dropLevel {
+			policy = OverflowDropNewest
+		}
+	}
+
+	if err := handler.queue.push(item, policy); err != nil {
+		handler.handleError(err)
+	}
+}
+
+// `nextBatch` returns the number of queued records of the next batch once it is full, the oldest record
+// has waited for the flush interval, or a flush is requested, and otherwise how long to wait for the interval.
+func (handler *HTTPHandler) nextBatch(items []httpItem, flushing bool) (int, time.Duration) {
+	count := handler.batchCount(items)
+	full := count < len(items) || count >= handler.maxBatchCount || len(items) >= handler.queue.size
+	wait := time.Until(items[0].queued.Add(handler.flushInterval))
+
+	if full || flushing || wait <= 0 {
+		return count, 0
+	}
+
+	return 0, wait
+}
+
+// `batchCount` returns the number of queued records fitting in the next batch.
+// A batch holds at least one record, even if it is larger than the maximum size.
+func (handler *HTTPHandler) batchCount(items []httpItem) int {
+	count, size := 0, 0
+
+	for _, item := range items {
+		if count == handler.maxBatchCount || (count > 0 && size+len(item.data)+1 > handler.maxBatchBytes) {
+			break
+		}
+
+		count++
+		size += len(item.data) + 1
+	}
+
+	return count
+}
+
+// `run` sends the batches until the handler is stopped.
+func (handler *HTTPHandler) run() {
+	for {
+		batch, ok := handler.queue.take(handler.nextBatch)
+		if !ok {
+			return
+		}
+
+		handler.deliver(batch)
+		handler.queue.sent()
+	}
+}
+
+// `deliver` sends the batch, retrying the records to retry with an exponential backoff.
+// The records that cannot be delivered are dropped and reported to the error handler.
+func (handler *HTTPHandler) deliver(batch []httpItem) {
+	backoff := handler.minBackoff
+
+	for attempt := 0; ; attempt++ {
+		retry, err := handler.send(batch)
+		if err == nil {
+			return
+		}
+
+		if len(retry) == 0 || attempt >= handler.maxRetries || !handler.queue.sleep(backoff) {
+			failed := len(batch)
+			if len(retry) > 0 {
+				failed = len(retry)
+			}
+
+			handler.queue.dropped.Add(uint64(failed))
+			handler.handleError(fmt.Errorf("%w: %d records not sent to %s: %w", ErrDeliveryFailed, failed, handler.url, err))
+
+			return
+		}
+
+		batch = retry
+		backoff = min(2*backoff, handler.maxBackoff)
+	}
+}
+
+// `send` posts the batch, returning the records to retry and the error, if any.
+func (handler *HTTPHandler) send(batch []httpItem) ([]httpItem, error) {
+	body, err := handler.encodeBatch(batch)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode batch: %w", err)
+	}
+
+	request, err := handler.newRequest(body)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := handler.client.Do(request)
+	if err != nil {
+		return batch, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer response.Body.Close()
+
+	responseBody, err := io.ReadAll(response.Body)
+	if err != nil {
+		return batch, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	return handler.checkResponse(response, responseBody, batch)
+}
+
+// `newRequest` returns the request posting the body, compressed if enabled.
+func (handler *HTTPHandler) newRequest(body []byte) (*http.Request, error) {
+	if handler.gzip {
+		var buffer bytes.Buffer
+
+		writer := gzip.NewWriter(&buffer)
+		if _, err := writer.Write(body); err != nil {
+			return nil, fmt.Errorf("failed to compress body: %w", err)
+		}
+
+		if err := writer.Close(); err != nil {
+			return nil, fmt.Errorf("failed to compress body: %w", err)
+		}
+
+		body = buffer.Bytes()
+	}
+
+	request, err := http.NewRequest(http.MethodPost, handler.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	request.Header.Set("Content-Type", handler.contentType())
+
+	for key, values := range handler.headers {
+		request.Header[key] = values
+	}
+
+	if handler.gzip {
+		request.Header.Set("Content-Encoding", "gzip")
+	}
+
+	return request, nil
+}
+
+// `checkHTTPStatus` retries the whole batch on a 5xx or 429 status, and fails without retrying on other errors.
+func checkHTTPStatus(response *http.Response, body []byte, items []httpItem) ([]httpItem, error) {
+	if response.StatusCode >= 200 && response.StatusCode < 300 {
+		return nil, nil
+	}
+
+	err := fmt.Errorf("server returned %s: %s", response.Status, strings.TrimSpace(string(body)))
+
+	if response.StatusCode >= 500 || response.StatusCode == http.StatusTooManyRequests {
+		return items, err
+	}
+
+	return nil, err
+}
+
+// `Flush` sends the queued records without waiting for their batch to be full,
+// and waits for them to be sent, at most for the flush timeout.
+func (handler *HTTPHandler) Flush() error {
+	if pending := handler.queue.flush(handler.flushTimeout, handler.minBackoff); pending > 0 {
+		return fmt.Errorf("%w: %d records not sent to %s", errFlushTimeout, pending, handler.url)
+	}
+
+	return nil
+}
+
+// `Close` stops accepting records, sends the queued records (waiting at most for the flush timeout),
+// then stops the worker. Closing an already closed handler does nothing.
+func (handler *HTTPHandler) Close() error {
+	if pending, _ := handler.queue.close(handler.flushTimeout, handler.minBackoff); pending > 0 {
+		return fmt.Errorf("%w: %d records not sent to %s", errFlushTimeout, pending, handler.url)
+	}
+
+	return nil
+}
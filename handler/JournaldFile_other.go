@@ -0,0 +1,21 @@
+//go:build !(darwin || dragonfly || freebsd || linux || netbsd || openbsd)
+
+package handler
+
+import (
+	"errors"
+	"net"
+)
+
+// `errJournaldFileNotSupported` is returned when descriptors cannot be sent over Unix sockets on the platform.
+var errJournaldFileNotSupported = errors.New("sending large journal entries is not supported on this platform")
+
+// `isDatagramTooLarge` reports no error as a payload too large, as payloads cannot be sent through a file.
+func isDatagramTooLarge(_ error) bool {
+	return false
+}
+
+// `sendJournaldFile` is not supported on this platform.
+func sendJournaldFile(_ *net.UnixConn, _ *net.UnixAddr, _ []byte) error {
+	return errJournaldFileNotSupported
+}
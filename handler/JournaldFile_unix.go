@@ -0,0 +1,48 @@
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd
+
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"syscall"
+)
+
+// `journaldFileDirectory` is the directory of the temporary files of large payloads,
+// a tmpfs on systemd hosts, falling back to the default temporary directory.
+const journaldFileDirectory = "/dev/shm"
+
+// `isDatagramTooLarge` checks if the error reports a payload too large for a datagram.
+func isDatagramTooLarge(err error) bool {
+	return errors.Is(err, syscall.EMSGSIZE) || errors.Is(err, syscall.ENOBUFS)
+}
+
+// `sendJournaldFile` writes the payload to an unlinked temporary file and sends its descriptor to the journal at `address`,
+// as the native protocol expects for payloads too large for a datagram.
+// A regular file is used rather than a sealed memfd, which would require golang.org/x/sys.
+func sendJournaldFile(conn *net.UnixConn, address *net.UnixAddr, payload []byte) error {
+	file, err := os.CreateTemp(journaldFileDirectory, "journal")
+	if err != nil {
+		file, err = os.CreateTemp("", "journal")
+		if err != nil {
+			return fmt.Errorf("failed to create payload file: %w", err)
+		}
+	}
+	defer file.Close()
+
+	if err := os.Remove(file.Name()); err != nil {
+		return fmt.Errorf("failed to unlink payload file: %w", err)
+	}
+
+	if _, err := file.Write(payload); err != nil {
+		return fmt.Errorf("failed to write payload file: %w", err)
+	}
+
+	if _, _, err := conn.WriteMsgUnix(nil, syscall.UnixRights(int(file.Fd())), address); err != nil {
+		return fmt.Errorf("failed to send payload file: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,264 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/ZertyCraft/GoLogger/formater"
+	"github.com/ZertyCraft/GoLogger/levels"
+	"github.com/ZertyCraft/GoLogger/record"
+)
+
+// `JournaldSocket` is the path of the socket of the journal native protocol.
+const JournaldSocket = "/run/systemd/journal/socket"
+
+// `JournaldHandler` is a handler that sends records to the systemd journal with its native protocol.
+// The message is formatted in `MESSAGE`, the level is sent as the syslog `PRIORITY`,
+// the caller (see `logger.SetReportCaller`) as `CODE_FILE`, `CODE_LINE` and `CODE_FUNC`,
+// the logger name as `LOGGER_NAME`, and each field under its name in upper case (see `journaldFieldName`).
+// Payloads too large for a datagram are written to a temporary file whose descriptor is sent instead.
+type JournaldHandler struct {
+	BaseHandler
+	socketPath string        // The path of the journal socket
+	identifier string        // The `SYSLOG_IDENTIFIER` of the entries
+	conn       *net.UnixConn // The unconnected socket the entries are sent from
+	closed     bool
+	mutex      sync.Mutex
+}
+
+// `NewJournaldHandler` returns a new `JournaldHandler` sending entries to `JournaldSocket`,
+// identified by the name of the executable. The default formater is a `LineFormater` writing only the message.
+func NewJournaldHandler() *JournaldHandler {
+	handler := &JournaldHandler{
+		BaseHandler: *NewBaseHandler(),
+		socketPath:  JournaldSocket,
+		identifier:  filepath.Base(os.Args[0]),
+		conn:        nil,
+		closed:      false,
+		mutex:       sync.Mutex{},
+	}
+
+	lineFormater := formater.NewLineFormater()
+	lineFormater.SetFormat("%m")
+	handler.formater = lineFormater
+
+	return handler
+}
+
+// ======== Setters ========
+// `SetSocketPath` sets the path of the journal socket.
+func (handler *JournaldHandler) SetSocketPath(socketPath string) {
+	handler.mutex.Lock()
+	defer handler.mutex.Unlock()
+
+	handler.socketPath = socketPath
+	handler.disconnect()
+}
+
+// `SetIdentifier` sets the `SYSLOG_IDENTIFIER` of the entries. No identifier is sent if it is empty.
+func (handler *JournaldHandler) SetIdentifier(identifier string) {
+	handler.mutex.Lock()
+	defer handler.mutex.Unlock()
+
+	handler.identifier = identifier
+}
+
+// ======== Getters ========
+// `GetSocketPath` returns the path of the journal socket.
+func (handler *JournaldHandler) GetSocketPath() string {
+	handler.mutex.Lock()
+	defer handler.mutex.Unlock()
+
+	return handler.socketPath
+}
+
+// `GetIdentifier` returns the `SYSLOG_IDENTIFIER` of the entries.
+func (handler *JournaldHandler) GetIdentifier() string {
+	handler.mutex.Lock()
+	defer handler.mutex.Unlock()
+
+	return handler.identifier
+}
+
+// ======== Methods ========
+// `Log` sends the message to the journal.
+func (handler *JournaldHandler) Log(level levels.Level, message string) {
+	handler.LogRecord(record.New(level, message))
+}
+
+// `LogRecord` sends the record to the journal.
+func (handler *JournaldHandler) LogRecord(rec record.Record) {
+	if !handler.shouldLog(rec) {
+		return
+	}
+
+	message, err := formater.FormatRecord(handler.formater, rec)
+	if err != nil {
+		handler.handleError(fmt.Errorf("failed to format message: %w", err))
+
+		return
+	}
+
+	handler.mutex.Lock()
+	defer handler.mutex.Unlock()
+
+	if handler.closed {
+		handler.handleError(ErrClosed)
+
+		return
+	}
+
+	if err := handler.send(handler.payload(rec, strings.TrimSuffix(message, "\n"))); err != nil {
+		handler.disconnect()
+		handler.handleError(fmt.Errorf("failed to send entry to the journal: %w", err))
+	}
+}
+
+// `payload` returns the entry of the record in the native protocol, without acquiring the lock.
+func (handler *JournaldHandler) payload(rec record.Record, message string) []byte {
+	var buffer bytes.Buffer
+
+	writeJournaldField(&buffer, "MESSAGE", message)
+	writeJournaldField(&buffer, "PRIORITY", strconv.Itoa(formater.SyslogSeverity(rec.Level)))
+
+	if handler.identifier != "" {
+		writeJournaldField(&buffer, "SYSLOG_IDENTIFIER", handler.identifier)
+	}
+
+	if rec.LoggerName != "" {
+		writeJournaldField(&buffer, "LOGGER_NAME", rec.LoggerName)
+	}
+
+	if rec.HasCaller() {
+		writeJournaldField(&buffer, "CODE_FILE", rec.Caller.File)
+		writeJournaldField(&buffer, "CODE_LINE", strconv.Itoa(rec.Caller.Line))
+
+		if rec.Caller.Function != "" {
+			writeJournaldField(&buffer, "CODE_FUNC", rec.Caller.Function)
+		}
+	}
+
+	for _, field := range rec.Fields {
+		if name := journaldFieldName(field.Key); name != "" {
+			writeJournaldField(&buffer, name, fmt.Sprint(field.Value))
+		}
+	}
+
+	return buffer.Bytes()
+}
+
+// `writeJournaldField` writes a field in the native protocol: `NAME=value` on a line,
+// or, if the value spans several lines, the name on a line followed by the little-endian 64-bit size of the value,
+// the value and a new line.
+func writeJournaldField(buffer *bytes.Buffer, name string, value string) {
+	buffer.WriteString(name)
+
+	if !strings.Contains(value, "\n") {
+		buffer.WriteString("=" + value + "\n")
+
+		return
+	}
+
+	buffer.WriteString("\n")
+	_ = binary.Write(buffer, binary.LittleEndian, uint64(len(value)))
+	buffer.WriteString(value + "\n")
+}
+
+// `journaldFieldName` returns a valid journal field name for the key: upper case letters, digits and underscores,
+// not starting with an underscore or a digit, and at most 64 characters. It returns "" if none is left.
+// Names of the fields written by the handler (`MESSAGE`, `PRIORITY`, `SYSLOG_*`, `CODE_*`, `LOGGER_NAME`),
+// names starting with an underscore (trusted fields set by journald) and names starting with a digit
+// are prefixed with `FIELD_`, so that a record field never adds a second value to them.
+func journaldFieldName(key string) string {
+	const maxLength = 64
+
+	name := strings.Map(func(char rune) rune {
+		switch {
+		case char >= 'a' && char <= 'z':
+			return char - 'a' + 'A'
+		case (char >= 'A' && char <= 'Z') || (char >= '0' && char <= '9'):
+			return char
+		default:
+			return '_'
+		}
+	}, key)
+
+	trimmed := strings.TrimLeft(name, "_")
+	if trimmed == "" {
+		return ""
+	}
+
+	if trimmed != name || isJournaldReservedField(trimmed) || (trimmed[0] >= '0' && trimmed[0] <= '9') {
+		trimmed = "FIELD_" + trimmed
+	}
+
+	name = trimmed
+
+	if len(name) > maxLength {
+		name = name[:maxLength]
+	}
+
+	return name
+}
+
+// `isJournaldReservedField` reports whether the name is the name of a field written by the `JournaldHandler`.
+func isJournaldReservedField(name string) bool {
+	switch name {
+	case "MESSAGE", "PRIORITY", "LOGGER_NAME":
+		return true
+	}
+
+	return strings.HasPrefix(name, "SYSLOG_") || strings.HasPrefix(name, "CODE_")
+}
+
+// `send` sends the payload in a datagram, or through a file if it is too large, without acquiring the lock.
+// The socket is not connected, as descriptors cannot be sent on connected datagram sockets.
+func (handler *JournaldHandler) send(payload []byte) error {
+	if handler.conn == nil {
+		conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: "", Net: "unixgram"})
+		if err != nil {
+			return fmt.Errorf("failed to open socket: %w", err)
+		}
+
+		handler.conn = conn
+	}
+
+	address := &net.UnixAddr{Name: handler.socketPath, Net: "unixgram"}
+
+	_, err := handler.conn.WriteToUnix(payload, address)
+	if isDatagramTooLarge(err) {
+		return sendJournaldFile(handler.conn, address, payload)
+	}
+
+	return err
+}
+
+// `disconnect` closes the connection, if any, without acquiring the lock. The next entry reconnects.
+func (handler *JournaldHandler) disconnect() {
+	if handler.conn != nil {
+		_ = handler.conn.Close()
+		handler.conn = nil
+	}
+}
+
+// `Close` closes the connection to the journal. Closing an already closed handler does nothing.
+func (handler *JournaldHandler) Close() error {
+	handler.mutex.Lock()
+	defer handler.mutex.Unlock()
+
+	if handler.closed {
+		return nil
+	}
+
+	handler.closed = true
+	handler.disconnect()
+
+	return nil
+}
@@ -0,0 +1,123 @@
+package handler
+
+import (
+	"errors"
+	"sort"
+	"sync"
+
+	"github.com/ZertyCraft/GoLogger/levels"
+	"github.com/ZertyCraft/GoLogger/record"
+)
+
+// `levelRoute` is a route of the `LevelRouter`.
+type levelRoute struct {
+	minLevel levels.Level
+	handler  Handler
+}
+
+// `LevelRouter` is a handler that logs each message with exactly one handler, chosen by level.
+// A message is sent to the route with the highest minimum level that is lower or equal to its level,
+// e.g. with routes DEBUG and WARN, DEBUG and INFO messages go to the first one, WARN and above to the second one.
+type LevelRouter struct {
+	BaseHandler
+	routes []levelRoute // Sorted by descending minimum level
+	mutex  sync.RWMutex
+}
+
+// `NewLevelRouter` returns a new `LevelRouter` without routes.
+// The level of the `LevelRouter` is DEBUG, so that filtering is left to the routes.
+func NewLevelRouter() *LevelRouter {
+	handler := &LevelRouter{
+		BaseHandler: *NewBaseHandler(),
+		routes:      make([]levelRoute, 0),
+		mutex:       sync.RWMutex{},
+	}
+	handler.Level = levels.DEBUG
+
+	return handler
+}
+
+// `AddRoute` routes messages at or above `minLevel` (up to the next route) to the given handler.
+// If a route already exists for `minLevel`, its handler is replaced.
+func (handler *LevelRouter) AddRoute(minLevel levels.Level, h Handler) {
+	handler.mutex.Lock()
+	defer handler.mutex.Unlock()
+
+	for i, route := range handler.routes {
+		if route.minLevel == minLevel {
+			handler.routes[i].handler = h
+
+			return
+		}
+	}
+
+	handler.routes = append(handler.routes, levelRoute{minLevel: minLevel, handler: h})
+
+	sort.Slice(handler.routes, func(i, j int) bool {
+		return handler.routes[i].minLevel > handler.routes[j].minLevel
+	})
+}
+
+// `RemoveRoute` removes the route for `minLevel`.
+func (handler *LevelRouter) RemoveRoute(minLevel levels.Level) {
+	handler.mutex.Lock()
+	defer handler.mutex.Unlock()
+
+	for i, route := range handler.routes {
+		if route.minLevel == minLevel {
+			handler.routes = append(handler.routes[:i], handler.routes[i+1:]...)
+
+			return
+		}
+	}
+}
+
+// `Log` logs the message with the handler of the matching route, if any.
+func (handler *LevelRouter) Log(level levels.Level, message string) {
+	handler.LogRecord(record.New(level, message))
+}
+
+// `LogRecord` logs the record with the handler of the matching route, if any.
+func (handler *LevelRouter) LogRecord(rec record.Record) {
+	if !handler.shouldLog(rec) {
+		return
+	}
+
+	handler.mutex.RLock()
+	defer handler.mutex.RUnlock()
+
+	for _, route := range handler.routes {
+		if rec.Level >= route.minLevel {
+			LogRecord(route.handler, rec)
+
+			return
+		}
+	}
+}
+
+// `Flush` flushes the handler of every route implementing `Flusher`, joining their errors.
+func (handler *LevelRouter) Flush() error {
+	handler.mutex.RLock()
+	defer handler.mutex.RUnlock()
+
+	errs := make([]error, 0, len(handler.routes))
+	for _, route := range handler.routes {
+		errs = append(errs, flushHandler(route.handler))
+	}
+
+	return errors.Join(errs...)
+}
+
+// `Close` closes the handler of every route implementing `Closer` and flushes the other ones,
+// joining their errors.
+func (handler *LevelRouter) Close() error {
+	handler.mutex.RLock()
+	defer handler.mutex.RUnlock()
+
+	errs := make([]error, 0, len(handler.routes))
+	for _, route := range handler.routes {
+		errs = append(errs, closeHandler(route.handler))
+	}
+
+	return errors.Join(errs...)
+}
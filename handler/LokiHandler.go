@@ -0,0 +1,208 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// `LokiHandler` is a handler that pushes records to Grafana Loki with its JSON push API.
+// It is an `HTTPHandler` grouping the records of each batch into streams by their labels:
+// static labels, the level (`level`), the logger name (`logger`) and selected fields.
+// Static and field labels named `level` or `logger` are prefixed with `field_`, so that they never replace
+// the built-in labels.
+// The entries of each stream are sorted by time, as Loki expects.
+// The lines are formatted with the formater of the handler (a `JSONFormater` by default).
+type LokiHandler struct {
+	*HTTPHandler
+	labels      map[string]string // The static labels of every stream
+	levelLabel  bool              // Whether the level is a label
+	loggerLabel bool              // Whether the logger name is a label
+	fieldLabels []string          // The fields used as labels
+}
+
+// `lokiReservedLabels` are the names of the built-in labels of the `LokiHandler`.
+var lokiReservedLabels = map[string]bool{"level": true, "logger": true}
+
+// `lokiStream` is a stream of the Loki push API: its labels and its `[timestamp, line]` entries.
+type lokiStream struct {
+	Stream map[string]string `json:"stream"`
+	Values [][2]string       `json:"values"`
+}
+
+// `NewLokiHandler` returns a new `LokiHandler` pushing records to `url`,
+// e.g. "http://localhost:3100/loki/api/v1/push", labelled with their level and logger name.
+func NewLokiHandler(url string) *LokiHandler {
+	handler := &LokiHandler{
+		HTTPHandler: NewHTTPHandler(url),
+		labels:      make(map[string]string),
+		levelLabel:  true,
+		loggerLabel: true,
+		fieldLabels: nil,
+	}
+	handler.encodeBatch = handler.pushBody
+
+	return handler
+}
+
+// ======== Setters ========
+// `SetLabel` sets a static label of every stream, e.g. the application or the environment.
+// The names of the built-in labels are prefixed with `field_`.
+func (handler *LokiHandler) SetLabel(name string, value string) {
+	handler.labels[lokiUserLabelName(name)] = value
+}
+
+// `SetLevelLabel` sets whether the level of the records is a label.
+func (handler *LokiHandler) SetLevelLabel(levelLabel bool) {
+	handler.levelLabel = levelLabel
+}
+
+// `SetLoggerNameLabel` sets whether the logger name of the records is a label.
+func (handler *LokiHandler) SetLoggerNameLabel(loggerLabel bool) {
+	handler.loggerLabel = loggerLabel
+}
+
+// `SetFieldLabels` sets the fields used as labels. Keep them few and of low cardinality,
+// as each set of label values is a separate stream in Loki.
+func (handler *LokiHandler) SetFieldLabels(fields ...string) {
+	handler.fieldLabels = fields
+}
+
+// ======== Getters ========
+// `GetLabels` returns the static labels of every stream.
+func (handler *LokiHandler) GetLabels() map[string]string {
+	return handler.labels
+}
+
+// `GetFieldLabels` returns the fields used as labels.
+func (handler *LokiHandler) GetFieldLabels() []string {
+	return handler.fieldLabels
+}
+
+// ======== Methods ========
+// `streamLabels` returns the labels of the stream of the record.
+func (handler *LokiHandler) streamLabels(item httpItem) map[string]string {
+	labels := make(map[string]string, len(handler.labels)+2+len(handler.fieldLabels))
+
+	for name, value := range handler.labels {
+		labels[name] = value
+	}
+
+	if handler.levelLabel {
+		labels["level"] = strings.ToLower(item.rec.Level.String())
+	}
+
+	if handler.loggerLabel && item.rec.LoggerName != "" {
+		labels["logger"] = item.rec.LoggerName
+	}
+
+	for _, field := range handler.fieldLabels {
+		if value, ok := item.rec.Field(field); ok {
+			labels[lokiUserLabelName(field)] = fmt.Sprint(value)
+		}
+	}
+
+	return labels
+}
+
+// `pushBody` returns the push request of the batch, with a stream per set of labels,
+// in the order of their first record, and the entries of each stream sorted by time.
+func (handler *LokiHandler) pushBody(items []httpItem) ([]byte, error) {
+	streams := make([]*lokiStream, 0)
+	times := make(map[*lokiStream][]int64)
+	byKey := make(map[string]*lokiStream)
+
+	for _, item := range items {
+		labels := handler.streamLabels(item)
+		key := lokiStreamKey(labels)
+
+		stream, ok := byKey[key]
+		if !ok {
+			stream = &lokiStream{Stream: labels, Values: make([][2]string, 0)}
+			byKey[key] = stream
+			streams = append(streams, stream)
+		}
+
+		timestamp := item.rec.Time.UnixNano()
+		stream.Values = append(stream.Values, [2]string{strconv.FormatInt(timestamp, 10), string(item.data)})
+		times[stream] = append(times[stream], timestamp)
+	}
+
+	for _, stream := range streams {
+		sort.Stable(lokiEntries{values: stream.Values, times: times[stream]})
+	}
+
+	body, err := json.Marshal(map[string][]*lokiStream{"streams": streams})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode streams: %w", err)
+	}
+
+	return body, nil
+}
+
+// `lokiEntries` sorts the entries of a stream by their timestamps.
+type lokiEntries struct {
+	values [][2]string
+	times  []int64
+}
+
+func (entries lokiEntries) Len() int {
+	return len(entries.values)
+}
+
+func (entries lokiEntries) Less(i, j int) bool {
+	return entries.times[i] < entries.times[j]
+}
+
+func (entries lokiEntries) Swap(i, j int) {
+	entries.values[i], entries.values[j] = entries.values[j], entries.values[i]
+	entries.times[i], entries.times[j] = entries.times[j], entries.times[i]
+}
+
+// `lokiStreamKey` returns a key identifying the set of labels.
+func lokiStreamKey(labels map[string]string) string {
+	names := make([]string, 0, len(labels))
+	for name := range labels {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	var builder strings.Builder
+
+	for _, name := range names {
+		builder.WriteString(strconv.Quote(name) + "=" + strconv.Quote(labels[name]) + ",")
+	}
+
+	return builder.String()
+}
+
+// `lokiLabelName` returns a valid label name: letters, digits and underscores, not starting with a digit.
+func lokiLabelName(name string) string {
+	sanitized := strings.Map(func(char rune) rune {
+		if (char >= 'a' && char <= 'z') || (char >= 'A' && char <= 'Z') || (char >= '0' && char <= '9') || char == '_' {
+			return char
+		}
+
+		return '_'
+	}, name)
+
+	if sanitized == "" || (sanitized[0] >= '0' && sanitized[0] <= '9') {
+		sanitized = "_" + sanitized
+	}
+
+	return sanitized
+}
+
+// `lokiUserLabelName` returns the label name of a static or field label,
+// prefixed with `field_` if it is the name of a built-in label.
+func lokiUserLabelName(name string) string {
+	sanitized := lokiLabelName(name)
+	if lokiReservedLabels[sanitized] {
+		return "field_" + sanitized
+	}
+
+	return sanitized
+}
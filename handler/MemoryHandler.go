@@ -0,0 +1,200 @@
+package handler
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/ZertyCraft/GoLogger/levels"
+	"github.com/ZertyCraft/GoLogger/record"
+)
+
+// `MemoryHandler` is a handler that keeps the last records in memory and logs them with another handler
+// only when a record at or above the trigger level arrives, to get the context of an error.
+// Records that are pushed out of the buffer before a trigger are discarded.
+// If a key field is set, records are buffered per value of this field (e.g. per request id).
+type MemoryHandler struct {
+	BaseHandler
+	target       Handler
+	capacity     int                        // The number of records kept per buffer
+	triggerLevel levels.Level               // The level from which the buffered records are logged
+	keyField     string                     // The field used to split records in buffers (disabled if empty)
+	maxKeys      int                        // The maximum number of buffers kept when a key field is set (unlimited if <= 0)
+	buffers      map[string][]record.Record // The buffers by key value
+	keys         []string                   // The keys of the buffers, oldest first
+	mutex        sync.Mutex
+}
+
+const (
+	// `defaultTriggerLevel` is the default value for the `triggerLevel` field of the `MemoryHandler`.
+	defaultTriggerLevel = levels.ERROR
+	// `defaultMaxKeys` is the default value for the `maxKeys` field of the `MemoryHandler`.
+	defaultMaxKeys = 1000
+)
+
+// `NewMemoryHandler` returns a new `MemoryHandler` keeping the last `capacity` records before logging them with `target`.
+// The level of the `MemoryHandler` is DEBUG, so that all records are buffered.
+func NewMemoryHandler(target Handler, capacity int) *MemoryHandler {
+	handler := &MemoryHandler{
+		BaseHandler:  *NewBaseHandler(),
+		target:       target,
+		capacity:     capacity,
+		triggerLevel: defaultTriggerLevel,
+		keyField:     "",
+		maxKeys:      defaultMaxKeys,
+		buffers:      make(map[string][]record.Record),
+		keys:         make([]string, 0),
+		mutex:        sync.Mutex{},
+	}
+	handler.Level = levels.DEBUG
+
+	return handler
+}
+
+// ======== Setters ========
+// `SetTriggerLevel` sets the value of the `triggerLevel` field of the `MemoryHandler`.
+func (handler *MemoryHandler) SetTriggerLevel(triggerLevel levels.Level) {
+	handler.triggerLevel = triggerLevel
+}
+
+// `SetKeyField` sets the field used to buffer records separately (e.g. "request_id").
+// Records without this field share the same buffer.
+func (handler *MemoryHandler) SetKeyField(keyField string) {
+	handler.keyField = keyField
+}
+
+// `SetMaxKeys` sets the maximum number of buffers kept when a key field is set.
+// When a new key exceeds it, the buffer of the oldest key is discarded. The number of buffers is unlimited if it is <= 0.
+func (handler *MemoryHandler) SetMaxKeys(maxKeys int) {
+	handler.maxKeys = maxKeys
+}
+
+// ======== Getters ========
+// `GetTriggerLevel` returns the value of the `triggerLevel` field of the `MemoryHandler`.
+func (handler *MemoryHandler) GetTriggerLevel() levels.Level {
+	return handler.triggerLevel
+}
+
+// `GetKeyField` returns the value of the `keyField` field of the `MemoryHandler`.
+func (handler *MemoryHandler) GetKeyField() string {
+	return handler.keyField
+}
+
+// `GetMaxKeys` returns the value of the `maxKeys` field of the `MemoryHandler`.
+func (handler *MemoryHandler) GetMaxKeys() int {
+	return handler.maxKeys
+}
+
+// `GetTarget` returns the handler the records are logged with.
+func (handler *MemoryHandler) GetTarget() Handler {
+	return handler.target
+}
+
+// ======== Methods ========
+// `Log` buffers the message, or logs the buffered records and the message if its level reaches the trigger level.
+func (handler *MemoryHandler) Log(level levels.Level, message string) {
+	handler.LogRecord(record.New(level, message))
+}
+
+// `LogRecord` buffers the record, or logs the buffered records and the record if its level reaches the trigger level.
+func (handler *MemoryHandler) LogRecord(rec record.Record) {
+	if !handler.shouldLog(rec) {
+		return
+	}
+
+	key := handler.getKey(rec)
+
+	handler.mutex.Lock()
+
+	if rec.Level < handler.triggerLevel {
+		handler.push(key, rec)
+		handler.mutex.Unlock()
+
+		return
+	}
+
+	buffered := handler.buffers[key]
+	handler.removeKey(key)
+	handler.mutex.Unlock()
+
+	for _, bufferedRecord := range buffered {
+		LogRecord(handler.target, bufferedRecord)
+	}
+
+	LogRecord(handler.target, rec)
+}
+
+// `Discard` discards the buffered records of the given key, e.g. when a request ends without error.
+func (handler *MemoryHandler) Discard(key string) {
+	handler.mutex.Lock()
+	defer handler.mutex.Unlock()
+
+	handler.removeKey(key)
+}
+
+// `getKey` returns the buffer key of the record.
+func (handler *MemoryHandler) getKey(rec record.Record) string {
+	if handler.keyField == "" {
+		return ""
+	}
+
+	value, ok := rec.Field(handler.keyField)
+	if !ok {
+		return ""
+	}
+
+	return fmt.Sprint(value)
+}
+
+// `push` adds the record to the buffer of the key, dropping the oldest record when the buffer is full.
+func (handler *MemoryHandler) push(key string, rec record.Record) {
+	if handler.capacity <= 0 {
+		return
+	}
+
+	buffer, ok := handler.buffers[key]
+	if !ok {
+		for handler.maxKeys > 0 && len(handler.keys) >= handler.maxKeys {
+			handler.removeKey(handler.keys[0])
+		}
+
+		handler.keys = append(handler.keys, key)
+	}
+
+	if len(buffer) >= handler.capacity {
+		buffer = buffer[len(buffer)-handler.capacity+1:]
+	}
+
+	handler.buffers[key] = append(buffer, rec)
+}
+
+// `removeKey` removes the buffer of the key.
+func (handler *MemoryHandler) removeKey(key string) {
+	if _, ok := handler.buffers[key]; !ok {
+		return
+	}
+
+	delete(handler.buffers, key)
+
+	for i, current := range handler.keys {
+		if current == key {
+			handler.keys = append(handler.keys[:i], handler.keys[i+1:]...)
+
+			break
+		}
+	}
+}
+
+// `Flush` flushes the target if it implements `Flusher`. Buffered records are kept.
+func (handler *MemoryHandler) Flush() error {
+	return flushHandler(handler.target)
+}
+
+// `Close` discards the buffered records, then closes the target if it implements `Closer`, or flushes it otherwise.
+func (handler *MemoryHandler) Close() error {
+	handler.mutex.Lock()
+	handler.buffers = make(map[string][]record.Record)
+	handler.keys = handler.keys[:0]
+	handler.mutex.Unlock()
+
+	return closeHandler(handler.target)
+}
@@ -0,0 +1,104 @@
+package handler
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/ZertyCraft/GoLogger/levels"
+	"github.com/ZertyCraft/GoLogger/record"
+)
+
+// `MultiHandler` is a handler that logs messages with several handlers, as a single unit.
+type MultiHandler struct {
+	BaseHandler
+	handlers []Handler
+	mutex    sync.RWMutex
+}
+
+// `NewMultiHandler` returns a new `MultiHandler` logging messages with the given handlers.
+// The level of the `MultiHandler` is DEBUG, so that filtering is left to the handlers.
+func NewMultiHandler(handlers ...Handler) *MultiHandler {
+	handler := &MultiHandler{
+		BaseHandler: *NewBaseHandler(),
+		handlers:    append(make([]Handler, 0, len(handlers)), handlers...),
+		mutex:       sync.RWMutex{},
+	}
+	handler.Level = levels.DEBUG
+
+	return handler
+}
+
+// `AddHandler` adds a handler to the `MultiHandler`.
+func (handler *MultiHandler) AddHandler(h Handler) {
+	handler.mutex.Lock()
+	defer handler.mutex.Unlock()
+
+	handler.handlers = append(handler.handlers, h)
+}
+
+// `RemoveHandler` removes a handler from the `MultiHandler`.
+func (handler *MultiHandler) RemoveHandler(h Handler) {
+	handler.mutex.Lock()
+	defer handler.mutex.Unlock()
+
+	for i, current := range handler.handlers {
+		if current == h {
+			handler.handlers = append(handler.handlers[:i], handler.handlers[i+1:]...)
+
+			return
+		}
+	}
+}
+
+// `GetHandlers` returns a copy of the handlers of the `MultiHandler`.
+func (handler *MultiHandler) GetHandlers() []Handler {
+	handler.mutex.RLock()
+	defer handler.mutex.RUnlock()
+
+	return append(make([]Handler, 0, len(handler.handlers)), handler.handlers...)
+}
+
+// `Log` logs the message with every handler.
+func (handler *MultiHandler) Log(level levels.Level, message string) {
+	handler.LogRecord(record.New(level, message))
+}
+
+// `LogRecord` logs the record with every handler.
+func (handler *MultiHandler) LogRecord(rec record.Record) {
+	if !handler.shouldLog(rec) {
+		return
+	}
+
+	handler.mutex.RLock()
+	defer handler.mutex.RUnlock()
+
+	for _, h := range handler.handlers {
+		LogRecord(h, rec)
+	}
+}
+
+// `Flush` flushes every handler implementing `Flusher`, joining their errors.
+func (handler *MultiHandler) Flush() error {
+	handler.mutex.RLock()
+	defer handler.mutex.RUnlock()
+
+	errs := make([]error, 0, len(handler.handlers))
+	for _, h := range handler.handlers {
+		errs = append(errs, flushHandler(h))
+	}
+
+	return errors.Join(errs...)
+}
+
+// `Close` closes every handler implementing `Closer` and flushes the other ones, joining their errors.
+func (handler *MultiHandler) Close() error {
+	handler.mutex.RLock()
+	defer handler.mutex.RUnlock()
+
+	errs := make([]error, 0, len(handler.handlers))
+	for _, h := range handler.handlers {
+		errs = append(errs, closeHandler(h))
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,342 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ZertyCraft/GoLogger/formater"
+	"github.com/ZertyCraft/GoLogger/levels"
+	"github.com/ZertyCraft/GoLogger/record"
+)
+
+// `Framing` defines how messages are delimited on stream connections (TCP).
+type Framing int
+
+const (
+	// `FramingNewline` ends each message with a line break.
+	FramingNewline Framing = iota
+	// `FramingOctetCounting` prefixes each message with its length and a space (RFC 6587).
+	FramingOctetCounting
+	// `FramingNullByte` ends each message with a null byte (e.g. GELF over TCP).
+	FramingNullByte
+)
+
+// `errUnsendableMessage` is wrapped by the errors of messages that can never be sent, which are dropped
+// instead of being sent again after reconnecting.
+var errUnsendableMessage = errors.New("message cannot be sent")
+
+// `errFlushTimeout` is returned when the queued messages could not be sent before the flush timeout.
+var errFlushTimeout = errors.New("timeout while sending queued messages")
+
+// `NetworkHandler` is a handler that sends formatted records to a collector over TCP or UDP.
+// Messages are pushed into a bounded queue and sent by a background goroutine,
+// which reconnects with an exponential backoff when the connection is lost.
+// When the queue is full, the oldest messages are dropped.
+// Over UDP, each message is sent in its own datagram, without framing.
+type NetworkHandler struct {
+	BaseHandler
+	network      string        // "tcp", "udp" or any network supported by `net.Dial`
+	address      string        // The address of the collector
+	framing      Framing       // The framing of the messages on stream connections
+	dialTimeout  time.Duration // The timeout of a connection attempt
+	writeTimeout time.Duration // The timeout of a write
+	minBackoff   time.Duration // The delay before the first reconnection attempt
+	maxBackoff   time.Duration // The maximum delay between reconnection attempts
+	flushTimeout time.Duration // The maximum time `Flush` and `Close` wait for the queue to be sent
+	queue        *deliveryQueue[[]byte]
+	// `writeMessage` writes a message to the connection, framed by default.
+	writeMessage func(conn net.Conn, message []byte) error
+}
+
+const (
+	// `defaultNetworkQueueSize` is the default value for the `queueSize` field of the `NetworkHandler`.
+	defaultNetworkQueueSize = 1024
+	// `defaultDialTimeout` is the default value for the `dialTimeout` field of the `NetworkHandler`.
+	defaultDialTimeout = 5 * time.Second
+	// `defaultWriteTimeout` is the default value for the `writeTimeout` field of the `NetworkHandler`.
+	defaultWriteTimeout = 5 * time.Second
+	// `defaultMinBackoff` is the default value for the `minBackoff` field of the `NetworkHandler`.
+	defaultMinBackoff = 100 * time.Millisecond
+	// `defaultMaxBackoff` is the default value for the `maxBackoff` field of the `NetworkHandler`.
+	defaultMaxBackoff = 30 * time.Second
+	// `defaultFlushTimeout` is the default value for the `flushTimeout` field of the `NetworkHandler`.
+	defaultFlushTimeout = 5 * time.Second
+)
+
+// `NewNetworkHandler` returns a new `NetworkHandler` sending messages to `address` over `network` ("tcp" or "udp").
+// The connection is opened when the first message is logged. The default formater is a `LineFormater`.
+func NewNetworkHandler(network string, address string) *NetworkHandler {
+	handler := &NetworkHandler{
+		BaseHandler:  *NewBaseHandler(),
+		network:      network,
+		address:      address,
+		framing:      FramingNewline,
+		dialTimeout:  defaultDialTimeout,
+		writeTimeout: defaultWriteTimeout,
+		minBackoff:   defaultMinBackoff,
+		maxBackoff:   defaultMaxBackoff,
+		flushTimeout: defaultFlushTimeout,
+		queue:        nil,
+		writeMessage: nil,
+	}
+	handler.formater = formater.NewLineFormater()
+	handler.queue = newDeliveryQueue[[]byte](defaultNetworkQueueSize, handler.run)
+	handler.writeMessage = handler.writeFramed
+
+	return handler
+}
+
+// ======== Setters ========
+// `SetFraming` sets the value of the `framing` field of the `NetworkHandler`.
+func (handler *NetworkHandler) SetFraming(framing Framing) {
+	handler.framing = framing
+}
+
+// `SetQueueSize` sets the maximum number of queued messages.
+// Sizes below 1 are set to 1, so that the message being logged always fits in the queue.
+func (handler *NetworkHandler) SetQueueSize(queueSize int) {
+	handler.queue.setSize(queueSize)
+}
+
+// `SetDialTimeout` sets the value of the `dialTimeout` field of the `NetworkHandler`.
+func (handler *NetworkHandler) SetDialTimeout(dialTimeout time.Duration) {
+	handler.dialTimeout = dialTimeout
+}
+
+// `SetWriteTimeout` sets the value of the `writeTimeout` field of the `NetworkHandler`.
+func (handler *NetworkHandler) SetWriteTimeout(writeTimeout time.Duration) {
+	handler.writeTimeout = writeTimeout
+}
+
+// `SetBackoff` sets the delay before the first reconnection attempt,
+// doubled after each failed attempt up to `maxBackoff`.
+func (handler *NetworkHandler) SetBackoff(minBackoff time.Duration, maxBackoff time.Duration) {
+	handler.minBackoff = minBackoff
+	handler.maxBackoff = maxBackoff
+}
+
+// `SetFlushTimeout` sets the value of the `flushTimeout` field of the `NetworkHandler`.
+func (handler *NetworkHandler) SetFlushTimeout(flushTimeout time.Duration) {
+	handler.flushTimeout = flushTimeout
+}
+
+// ======== Getters ========
+// `GetNetwork` returns the value of the `network` field of the `NetworkHandler`.
+func (handler *NetworkHandler) GetNetwork() string {
+	return handler.network
+}
+
+// `GetAddress` returns the value of the `address` field of the `NetworkHandler`.
+func (handler *NetworkHandler) GetAddress() string {
+	return handler.address
+}
+
+// `GetFraming` returns the value of the `framing` field of the `NetworkHandler`.
+func (handler *NetworkHandler) GetFraming() Framing {
+	return handler.framing
+}
+
+// `Dropped` returns the number of messages dropped because the queue was full.
+func (handler *NetworkHandler) Dropped() uint64 {
+	return handler.queue.dropped.Load()
+}
+
+// `Queued` returns the number of messages waiting to be sent.
+func (handler *NetworkHandler) Queued() int {
+	return handler.queue.length()
+}
+
+// ======== Methods ========
+// `Log` formats the message and queues it to be sent.
+func (handler *NetworkHandler) Log(level levels.Level, message string) {
+	handler.LogRecord(record.New(level, message))
+}
+
+// `LogRecord` formats the record and queues it to be sent.
+func (handler *NetworkHandler) LogRecord(rec record.Record) {
+	if !handler.shouldLog(rec) {
+		return
+	}
+
+	message, err := formater.FormatRecord(handler.formater, rec)
+	if err != nil {
+		handler.handleError(fmt.Errorf("failed to format message: %w", err))
+
+		return
+	}
+
+	// Drop the oldest messages when the queue is full
+	if err := handler.queue.push([]byte(strings.TrimSuffix(message, "\n")), OverflowDropOldest); err != nil {
+		handler.handleError(err)
+	}
+}
+
+// `isDatagram` checks if the network sends datagrams (UDP) instead of a stream (TCP).
+func (handler *NetworkHandler) isDatagram() bool {
+	return strings.HasPrefix(handler.network, "udp") || strings.HasPrefix(handler.network, "unixgram")
+}
+
+// `frame` returns the bytes sent for the message.
+func (handler *NetworkHandler) frame(message []byte) []byte {
+	if handler.isDatagram() {
+		return message
+	}
+
+	if handler.framing == FramingOctetCounting {
+		return append([]byte(strconv.Itoa(len(message))+" "), message...)
+	}
+
+	if handler.framing == FramingNullByte {
+		return append(message, 0)
+	}
+
+	return append(message, '\n')
+}
+
+// `nextMessage` takes the queued messages one at a time, as soon as they are queued.
+func nextMessage([][]byte, bool) (int, time.Duration) {
+	return 1, 0
+}
+
+// `watchClosed` returns a channel closed when the peer closes the stream connection, discarding what it sends.
+// Writes to a connection closed by the peer succeed until the peer resets it, so the messages would be lost.
+// The channel of a datagram connection is never closed.
+func (handler *NetworkHandler) watchClosed(conn net.Conn) <-chan struct{} {
+	closed := make(chan struct{})
+
+	if handler.isDatagram() {
+		return closed
+	}
+
+	go func() {
+		defer close(closed)
+
+		_, _ = io.Copy(io.Discard, conn)
+	}()
+
+	return closed
+}
+
+// `run` sends the queued messages until the handler is stopped, reconnecting when needed.
+func (handler *NetworkHandler) run() {
+	var (
+		conn       net.Conn
+		connClosed <-chan struct{}
+	)
+
+	defer func() {
+		if conn != nil {
+			conn.Close()
+		}
+	}()
+
+	backoff := handler.minBackoff
+	reported := false
+
+	for {
+		messages, ok := handler.queue.take(nextMessage)
+		if !ok {
+			return
+		}
+
+		message := messages[0]
+
+		// Reconnect if the peer closed the connection
+		if conn != nil {
+			select {
+			case <-connClosed:
+				conn.Close()
+				conn = nil
+			default:
+			}
+		}
+
+		if conn == nil {
+			var err error
+
+			conn, err = net.DialTimeout(handler.network, handler.address, handler.dialTimeout)
+			if err != nil {
+				handler.queue.requeue(message)
+
+				// Report the failure once per disconnection
+				if !reported {
+					handler.handleError(fmt.Errorf("failed to connect to %s: %w", handler.address, err))
+					reported = true
+				}
+
+				if !handler.queue.sleep(backoff) {
+					return
+				}
+
+				backoff = min(2*backoff, handler.maxBackoff)
+
+				continue
+			}
+
+			connClosed = handler.watchClosed(conn)
+			backoff = handler.minBackoff
+			reported = false
+		}
+
+		if err := handler.write(conn, message); err != nil {
+			if errors.Is(err, errUnsendableMessage) {
+				handler.queue.dropped.Add(1)
+				handler.queue.sent()
+				handler.handleError(fmt.Errorf("failed to send message to %s: %w", handler.address, err))
+
+				continue
+			}
+
+			handler.queue.requeue(message)
+			handler.handleError(fmt.Errorf("failed to send message to %s: %w", handler.address, err))
+			conn.Close()
+			conn = nil
+
+			continue
+		}
+
+		handler.queue.sent()
+	}
+}
+
+// `write` writes the message to the connection, with the write timeout.
+func (handler *NetworkHandler) write(conn net.Conn, message []byte) error {
+	if err := conn.SetWriteDeadline(time.Now().Add(handler.writeTimeout)); err != nil {
+		return fmt.Errorf("failed to set write deadline: %w", err)
+	}
+
+	return handler.writeMessage(conn, message)
+}
+
+// `writeFramed` writes the framed message to the connection.
+func (handler *NetworkHandler) writeFramed(conn net.Conn, message []byte) error {
+	if _, err := conn.Write(handler.frame(message)); err != nil {
+		return fmt.Errorf("failed to write: %w", err)
+	}
+
+	return nil
+}
+
+// `Flush` waits for the queued messages to be sent, at most for the flush timeout.
+func (handler *NetworkHandler) Flush() error {
+	if pending := handler.queue.flush(handler.flushTimeout, handler.minBackoff); pending > 0 {
+		return fmt.Errorf("%w: %d messages not sent to %s", errFlushTimeout, pending, handler.address)
+	}
+
+	return nil
+}
+
+// `Close` stops accepting messages, waits for the queued messages to be sent (at most for the flush timeout),
+// then closes the connection. Closing an already closed handler does nothing.
+func (handler *NetworkHandler) Close() error {
+	if pending, _ := handler.queue.close(handler.flushTimeout, handler.minBackoff); pending > 0 {
+		return fmt.Errorf("%w: %d messages not sent to %s", errFlushTimeout, pending, handler.address)
+	}
+
+	return nil
+}
@@ -0,0 +1,264 @@
+package handler
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ZertyCraft/GoLogger/levels"
+	"github.com/ZertyCraft/GoLogger/record"
+)
+
+// `OTLPHandler` is a handler that exports records to an OpenTelemetry collector with OTLP/HTTP in JSON,
+// without depending on the OpenTelemetry SDK. It is an `HTTPHandler` mapping each record to the OpenTelemetry
+// log data model: the level as severity number and text, the message as body, the fields and the caller
+// as attributes, and the `trace_id` and `span_id` fields (hexadecimal) as trace context.
+// The records are grouped by logger name in instrumentation scopes, under the resource attributes of the handler.
+type OTLPHandler struct {
+	*HTTPHandler
+	resource []otlpKeyValue // The resource attributes, e.g. `service.name`
+}
+
+// `otlpKeyValue` is an attribute of the OTLP JSON encoding.
+type otlpKeyValue struct {
+	Key   string         `json:"key"`
+	Value map[string]any `json:"value"`
+}
+
+// `otlpLogRecord` is a log record of the OTLP JSON encoding.
+type otlpLogRecord struct {
+	TimeUnixNano         string         `json:"timeUnixNano"`
+	ObservedTimeUnixNano string         `json:"observedTimeUnixNano"`
+	SeverityNumber       int            `json:"severityNumber"`
+	SeverityText         string         `json:"severityText"`
+	Body                 map[string]any `json:"body"`
+	Attributes           []otlpKeyValue `json:"attributes,omitempty"`
+	TraceID              string         `json:"traceId,omitempty"`
+	SpanID               string         `json:"spanId,omitempty"`
+}
+
+// `otlpScopeLogs` is the log records of an instrumentation scope in the OTLP JSON encoding.
+type otlpScopeLogs struct {
+	Scope      map[string]string `json:"scope"`
+	LogRecords []json.RawMessage `json:"logRecords"`
+}
+
+const (
+	// `OTLPTraceIDField` is the field holding the trace id of a record, in hexadecimal.
+	// Values that are not 32 hexadecimal characters are sent as attributes.
+	OTLPTraceIDField = "trace_id"
+	// `OTLPSpanIDField` is the field holding the span id of a record, in hexadecimal.
+	// Values that are not 16 hexadecimal characters are sent as attributes.
+	OTLPSpanIDField = "span_id"
+	// `otlpTraceIDLength` is the length of a trace id in hexadecimal.
+	otlpTraceIDLength = 32
+	// `otlpSpanIDLength` is the length of a span id in hexadecimal.
+	otlpSpanIDLength = 16
+)
+
+// `OTLPSeverity` returns the OpenTelemetry severity number of the log level.
+func OTLPSeverity(level levels.Level) int {
+	switch level {
+	case levels.DEBUG:
+		return 5
+	case levels.INFO:
+		return 9
+	case levels.WARN:
+		return 13
+	case levels.ERROR:
+		return 17
+	case levels.CRITICAL:
+		return 21
+	}
+
+	return 0
+}
+
+// `NewOTLPHandler` returns a new `OTLPHandler` exporting records to the collector at `endpoint`
+// (e.g. "http://localhost:4318"), with the `service.name` resource attribute "unknown_service:<executable>".
+func NewOTLPHandler(endpoint string) *OTLPHandler {
+	handler := &OTLPHandler{
+		HTTPHandler: NewHTTPHandler(strings.TrimSuffix(endpoint, "/") + "/v1/logs"),
+		resource:    nil,
+	}
+	handler.encodeRecord = handler.logRecord
+	handler.encodeBatch = handler.exportBody
+	handler.SetResourceAttribute("service.name", "unknown_service:"+filepath.Base(os.Args[0]))
+
+	return handler
+}
+
+// ======== Setters ========
+// `SetServiceName` sets the `service.name` resource attribute.
+func (handler *OTLPHandler) SetServiceName(serviceName string) {
+	handler.SetResourceAttribute("service.name", serviceName)
+}
+
+// `SetResourceAttribute` sets a resource attribute, e.g. `service.version` or `deployment.environment`.
+func (handler *OTLPHandler) SetResourceAttribute(key string, value any) {
+	attribute := otlpKeyValue{Key: key, Value: otlpValue(value)}
+
+	for i := range handler.resource {
+		if handler.resource[i].Key == key {
+			handler.resource[i] = attribute
+
+			return
+		}
+	}
+
+	handler.resource = append(handler.resource, attribute)
+}
+
+// ======== Getters ========
+// `GetResourceAttribute` returns the value of a resource attribute, and whether it is set.
+func (handler *OTLPHandler) GetResourceAttribute(key string) (any, bool) {
+	for _, attribute := range handler.resource {
+		if attribute.Key == key {
+			for _, value := range attribute.Value {
+				return value, true
+			}
+		}
+	}
+
+	return nil, false
+}
+
+// ======== Methods ========
+// `logRecord` returns the OTLP log record of the record.
+func (handler *OTLPHandler) logRecord(rec record.Record) ([]byte, error) {
+	logRecord := otlpLogRecord{
+		TimeUnixNano:         strconv.FormatInt(rec.Time.UnixNano(), 10),
+		ObservedTimeUnixNano: strconv.FormatInt(time.Now().UnixNano(), 10), // The time the handler received the record
+		SeverityNumber:       OTLPSeverity(rec.Level),
+		SeverityText:         rec.Level.String(),
+		Body:                 otlpValue(rec.Message),
+		Attributes:           make([]otlpKeyValue, 0, len(rec.Fields)),
+		TraceID:              "",
+		SpanID:               "",
+	}
+
+	// Invalid ids are kept as attributes, as the collector rejects the whole batch of a record with an invalid id
+	for _, field := range rec.Fields {
+		switch {
+		case field.Key == OTLPTraceIDField && isOTLPID(field.Value, otlpTraceIDLength):
+			logRecord.TraceID = fmt.Sprint(field.Value)
+		case field.Key == OTLPSpanIDField && isOTLPID(field.Value, otlpSpanIDLength):
+			logRecord.SpanID = fmt.Sprint(field.Value)
+		default:
+			logRecord.Attributes = append(logRecord.Attributes, otlpKeyValue{Key: field.Key, Value: otlpValue(field.Value)})
+		}
+	}
+
+	if rec.HasCaller() {
+		logRecord.Attributes = append(logRecord.Attributes,
+			otlpKeyValue{Key: "code.filepath", Value: otlpValue(rec.Caller.File)},
+			otlpKeyValue{Key: "code.lineno", Value: otlpValue(rec.Caller.Line)},
+			otlpKeyValue{Key: "code.function", Value: otlpValue(rec.Caller.Function)},
+		)
+	}
+
+	data, err := json.Marshal(logRecord)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode log record: %w", err)
+	}
+
+	return data, nil
+}
+
+// `exportBody` returns the export request of the batch, with an instrumentation scope per logger name
+// in the order of their first record.
+func (handler *OTLPHandler) exportBody(items []httpItem) ([]byte, error) {
+	scopes := make([]*otlpScopeLogs, 0)
+	byName := make(map[string]*otlpScopeLogs)
+
+	for _, item := range items {
+		scope, ok := byName[item.rec.LoggerName]
+		if !ok {
+			scope = &otlpScopeLogs{Scope: map[string]string{"name": item.rec.LoggerName}, LogRecords: nil}
+			byName[item.rec.LoggerName] = scope
+			scopes = append(scopes, scope)
+		}
+
+		scope.LogRecords = append(scope.LogRecords, json.RawMessage(item.data))
+	}
+
+	body, err := json.Marshal(map[string]any{
+		"resourceLogs": []map[string]any{{
+			"resource":  map[string]any{"attributes": handler.resource},
+			"scopeLogs": scopes,
+		}},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode export request: %w", err)
+	}
+
+	return body, nil
+}
+
+// `otlpValue` returns the `AnyValue` of the OTLP JSON encoding of the value.
+// 64-bit integers are encoded as strings, as in the JSON mapping of protocol buffers,
+// and unsigned integers larger than the maximum `int64` as string values.
+func otlpValue(value any) map[string]any {
+	switch value := value.(type) {
+	case string:
+		return map[string]any{"stringValue": value}
+	case bool:
+		return map[string]any{"boolValue": value}
+	case int:
+		return map[string]any{"intValue": strconv.FormatInt(int64(value), 10)}
+	case int8:
+		return map[string]any{"intValue": strconv.FormatInt(int64(value), 10)}
+	case int16:
+		return map[string]any{"intValue": strconv.FormatInt(int64(value), 10)}
+	case int32:
+		return map[string]any{"intValue": strconv.FormatInt(int64(value), 10)}
+	case int64:
+		return map[string]any{"intValue": strconv.FormatInt(value, 10)}
+	case uint:
+		return otlpUintValue(uint64(value))
+	case uint8:
+		return otlpUintValue(uint64(value))
+	case uint16:
+		return otlpUintValue(uint64(value))
+	case uint32:
+		return otlpUintValue(uint64(value))
+	case uint64:
+		return otlpUintValue(value)
+	case float32:
+		return map[string]any{"doubleValue": float64(value)}
+	case float64:
+		return map[string]any{"doubleValue": value}
+	case error:
+		return map[string]any{"stringValue": value.Error()}
+	}
+
+	return map[string]any{"stringValue": fmt.Sprint(value)}
+}
+
+// `otlpUintValue` returns the `AnyValue` of an unsigned integer: an `intValue` if it fits in an `int64`,
+// and a `stringValue` otherwise, as `AnyValue` has no unsigned integer type.
+func otlpUintValue(value uint64) map[string]any {
+	if value > math.MaxInt64 {
+		return map[string]any{"stringValue": strconv.FormatUint(value, 10)}
+	}
+
+	return map[string]any{"intValue": strconv.FormatUint(value, 10)}
+}
+
+// `isOTLPID` checks if the value is an id of `length` hexadecimal characters.
+func isOTLPID(value any, length int) bool {
+	id := fmt.Sprint(value)
+	if len(id) != length {
+		return false
+	}
+
+	_, err := hex.DecodeString(id)
+
+	return err == nil
+}
@@ -0,0 +1,208 @@
+package handler
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ZertyCraft/GoLogger/levels"
+	"github.com/ZertyCraft/GoLogger/record"
+)
+
+// `RateLimitPolicy` defines what the `RateLimitHandler` does with records exceeding the limit.
+type RateLimitPolicy int
+
+const (
+	// `RateLimitDrop` silently drops the records.
+	RateLimitDrop RateLimitPolicy = iota
+	// `RateLimitSummary` drops the records, then logs a summary of how many were dropped
+	// before the next record of the level that fits in the limit.
+	RateLimitSummary
+)
+
+// `tokenBucket` is a token bucket refilled at `rate` tokens per second, holding at most `burst` tokens.
+type tokenBucket struct {
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// `take` refills the bucket, then takes a token if one is available.
+func (bucket *tokenBucket) take(now time.Time) bool {
+	bucket.tokens += now.Sub(bucket.last).Seconds() * bucket.rate
+	if bucket.tokens > bucket.burst {
+		bucket.tokens = bucket.burst
+	}
+
+	bucket.last = now
+
+	if bucket.tokens < 1 {
+		return false
+	}
+
+	bucket.tokens--
+
+	return true
+}
+
+// `RateLimitHandler` is a handler that caps the throughput of records logged with another handler,
+// with a token bucket per level. Levels without a limit are not limited.
+type RateLimitHandler struct {
+	BaseHandler
+	target  Handler
+	policy  RateLimitPolicy
+	buckets map[levels.Level]*tokenBucket // The token buckets by level
+	dropped map[levels.Level]uint64       // The number of dropped records by level
+	pending map[levels.Level]int          // The number of dropped records not reported in a summary by level
+	mutex   sync.Mutex
+}
+
+// `defaultRateLimitPolicy` is the default value for the `policy` field of the `RateLimitHandler`.
+const defaultRateLimitPolicy = RateLimitDrop
+
+// `NewRateLimitHandler` returns a new `RateLimitHandler` logging with `target`, without limits.
+// The level of the `RateLimitHandler` is DEBUG, so that filtering is left to the target.
+func NewRateLimitHandler(target Handler) *RateLimitHandler {
+	handler := &RateLimitHandler{
+		BaseHandler: *NewBaseHandler(),
+		target:      target,
+		policy:      defaultRateLimitPolicy,
+		buckets:     make(map[levels.Level]*tokenBucket),
+		dropped:     make(map[levels.Level]uint64),
+		pending:     make(map[levels.Level]int),
+		mutex:       sync.Mutex{},
+	}
+	handler.Level = levels.DEBUG
+
+	return handler
+}
+
+// ======== Setters ========
+// `SetLimit` limits the records of the level to `ratePerSecond` records per second,
+// allowing bursts of `burst` records.
+func (handler *RateLimitHandler) SetLimit(level levels.Level, ratePerSecond float64, burst int) {
+	handler.mutex.Lock()
+	defer handler.mutex.Unlock()
+
+	handler.buckets[level] = &tokenBucket{
+		rate:   ratePerSecond,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// `RemoveLimit` removes the limit of the level.
+func (handler *RateLimitHandler) RemoveLimit(level levels.Level) {
+	handler.mutex.Lock()
+	defer handler.mutex.Unlock()
+
+	delete(handler.buckets, level)
+}
+
+// `SetPolicy` sets the value of the `policy` field of the `RateLimitHandler`.
+func (handler *RateLimitHandler) SetPolicy(policy RateLimitPolicy) {
+	handler.policy = policy
+}
+
+// ======== Getters ========
+// `GetPolicy` returns the value of the `policy` field of the `RateLimitHandler`.
+func (handler *RateLimitHandler) GetPolicy() RateLimitPolicy {
+	return handler.policy
+}
+
+// `GetTarget` returns the handler the records are logged with.
+func (handler *RateLimitHandler) GetTarget() Handler {
+	return handler.target
+}
+
+// `Dropped` returns the number of records of the level dropped because of the limit.
+func (handler *RateLimitHandler) Dropped(level levels.Level) uint64 {
+	handler.mutex.Lock()
+	defer handler.mutex.Unlock()
+
+	return handler.dropped[level]
+}
+
+// `DroppedTotal` returns the number of records of all levels dropped because of the limits.
+func (handler *RateLimitHandler) DroppedTotal() uint64 {
+	handler.mutex.Lock()
+	defer handler.mutex.Unlock()
+
+	total := uint64(0)
+	for _, dropped := range handler.dropped {
+		total += dropped
+	}
+
+	return total
+}
+
+// ======== Methods ========
+// `Log` logs the message with the target if it fits in the limit of its level.
+func (handler *RateLimitHandler) Log(level levels.Level, message string) {
+	handler.LogRecord(record.New(level, message))
+}
+
+// `LogRecord` logs the record with the target if it fits in the limit of its level.
+func (handler *RateLimitHandler) LogRecord(rec record.Record) {
+	if !handler.shouldLog(rec) {
+		return
+	}
+
+	handler.mutex.Lock()
+
+	bucket, limited := handler.buckets[rec.Level]
+	if limited && !bucket.take(time.Now()) {
+		handler.dropped[rec.Level]++
+
+		if handler.policy == RateLimitSummary {
+			handler.pending[rec.Level]++
+		}
+
+		handler.mutex.Unlock()
+
+		return
+	}
+
+	pending := handler.pending[rec.Level]
+	delete(handler.pending, rec.Level)
+	handler.mutex.Unlock()
+
+	if pending > 0 {
+		LogRecord(handler.target, handler.summary(rec.Level, rec.LoggerName, pending))
+	}
+
+	LogRecord(handler.target, rec)
+}
+
+// `summary` returns the record reporting the number of dropped records of the level.
+func (handler *RateLimitHandler) summary(level levels.Level, loggerName string, dropped int) record.Record {
+	summary := record.New(level, fmt.Sprintf("%d messages dropped by rate limit", dropped))
+	summary.LoggerName = loggerName
+	summary.Fields = []record.Field{{Key: "rate_limit_dropped", Value: dropped}}
+
+	return summary
+}
+
+// `Flush` flushes the target if it implements `Flusher`.
+func (handler *RateLimitHandler) Flush() error {
+	return flushHandler(handler.target)
+}
+
+// `Close` logs the summaries not reported yet,
+// then closes the target if it implements `Closer`, or flushes it otherwise.
+func (handler *RateLimitHandler) Close() error {
+	handler.mutex.Lock()
+	pending := handler.pending
+	handler.pending = make(map[levels.Level]int)
+	handler.mutex.Unlock()
+
+	for level := levels.DEBUG; level <= levels.CRITICAL; level++ {
+		if pending[level] > 0 {
+			LogRecord(handler.target, handler.summary(level, "", pending[level]))
+		}
+	}
+
+	return closeHandler(handler.target)
+}
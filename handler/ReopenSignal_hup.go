@@ -0,0 +1,13 @@
+//go:build !js
+
+package handler
+
+import (
+	"os"
+	"syscall"
+)
+
+// `defaultReopenSignals` returns the signals reopening the log file when none is given: SIGHUP.
+func defaultReopenSignals() []os.Signal {
+	return []os.Signal{syscall.SIGHUP}
+}
@@ -0,0 +1,10 @@
+//go:build js
+
+package handler
+
+import "os"
+
+// `defaultReopenSignals` returns no signal, as SIGHUP is not available on this platform.
+func defaultReopenSignals() []os.Signal {
+	return nil
+}
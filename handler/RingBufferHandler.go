@@ -0,0 +1,217 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ZertyCraft/GoLogger/formater"
+	"github.com/ZertyCraft/GoLogger/levels"
+	"github.com/ZertyCraft/GoLogger/record"
+)
+
+// `RecordQuery` selects records of a `RingBufferHandler`. Zero values do not filter.
+type RecordQuery struct {
+	MinLevel levels.Level // The minimum level of the records
+	Since    time.Time    // The records logged at or after this time
+	Until    time.Time    // The records logged at or before this time
+	Contains string       // The substring the message must contain
+	Limit    int          // The maximum number of records, keeping the most recent ones
+}
+
+// `RingBufferHandler` is a handler that keeps the last records in memory, so they can be queried
+// (e.g. to show them on a debug page). It implements `http.Handler` to render them as JSON or text.
+type RingBufferHandler struct {
+	BaseHandler
+	records []record.Record // The ring buffer
+	next    int             // The index of the next record to write
+	full    bool            // Whether the ring buffer has wrapped around
+	mutex   sync.RWMutex
+}
+
+// `NewRingBufferHandler` returns a new `RingBufferHandler` keeping the last `capacity` records.
+// A negative capacity is treated as zero, so that no record is kept.
+// The level of the `RingBufferHandler` is DEBUG, and its formater (used by the text rendering) is a `LineFormater`.
+func NewRingBufferHandler(capacity int) *RingBufferHandler {
+	handler := &RingBufferHandler{
+		BaseHandler: *NewBaseHandler(),
+		records:     make([]record.Record, max(capacity, 0)),
+		next:        0,
+		full:        false,
+		mutex:       sync.RWMutex{},
+	}
+	handler.Level = levels.DEBUG
+	handler.formater = formater.NewLineFormater()
+
+	return handler
+}
+
+// `Log` keeps the message in the ring buffer.
+func (handler *RingBufferHandler) Log(level levels.Level, message string) {
+	handler.LogRecord(record.New(level, message))
+}
+
+// `LogRecord` keeps the record in the ring buffer, replacing the oldest one if it is full.
+func (handler *RingBufferHandler) LogRecord(rec record.Record) {
+	if !handler.shouldLog(rec) || len(handler.records) == 0 {
+		return
+	}
+
+	handler.mutex.Lock()
+	defer handler.mutex.Unlock()
+
+	handler.records[handler.next] = rec
+	handler.next = (handler.next + 1) % len(handler.records)
+
+	if handler.next == 0 {
+		handler.full = true
+	}
+}
+
+// `Snapshot` returns a copy of the records, oldest first.
+func (handler *RingBufferHandler) Snapshot() []record.Record {
+	handler.mutex.RLock()
+	defer handler.mutex.RUnlock()
+
+	if !handler.full {
+		return append(make([]record.Record, 0, handler.next), handler.records[:handler.next]...)
+	}
+
+	snapshot := make([]record.Record, 0, len(handler.records))
+	snapshot = append(snapshot, handler.records[handler.next:]...)
+
+	return append(snapshot, handler.records[:handler.next]...)
+}
+
+// `Query` returns a copy of the records matching the query, oldest first.
+func (handler *RingBufferHandler) Query(query RecordQuery) []record.Record {
+	matching := make([]record.Record, 0)
+
+	for _, rec := range handler.Snapshot() {
+		if rec.Level < query.MinLevel ||
+			(!query.Since.IsZero() && rec.Time.Before(query.Since)) ||
+			(!query.Until.IsZero() && rec.Time.After(query.Until)) ||
+			!strings.Contains(rec.Message, query.Contains) {
+			continue
+		}
+
+		matching = append(matching, rec)
+	}
+
+	if query.Limit > 0 && len(matching) > query.Limit {
+		matching = matching[len(matching)-query.Limit:]
+	}
+
+	return matching
+}
+
+// `Reset` removes all the records.
+func (handler *RingBufferHandler) Reset() {
+	handler.mutex.Lock()
+	defer handler.mutex.Unlock()
+
+	clear(handler.records)
+	handler.next = 0
+	handler.full = false
+}
+
+// `jsonRecord` is the JSON representation of a record rendered by `ServeHTTP`.
+type jsonRecord struct {
+	Time    time.Time      `json:"time"`
+	Level   string         `json:"level"`
+	Message string         `json:"message"`
+	Logger  string         `json:"logger,omitempty"`
+	Fields  map[string]any `json:"fields,omitempty"`
+}
+
+// `ServeHTTP` renders the records matching the query parameters
+// `level`, `since`, `until` (RFC 3339), `contains` and `limit`.
+// The records are rendered as JSON, or as text with the formater of the handler if `format=text`.
+func (handler *RingBufferHandler) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
+	query, err := parseRecordQuery(request)
+	if err != nil {
+		http.Error(writer, err.Error(), http.StatusBadRequest)
+
+		return
+	}
+
+	records := handler.Query(query)
+
+	if request.URL.Query().Get("format") == "text" {
+		writer.Header().Set("Content-Type", "text/plain; charset=utf-8")
+
+		for _, rec := range records {
+			line, err := formater.FormatRecord(handler.formater, rec)
+			if err != nil {
+				handler.handleError(fmt.Errorf("failed to format message: %w", err))
+
+				continue
+			}
+
+			fmt.Fprintln(writer, strings.TrimSuffix(line, "\n"))
+		}
+
+		return
+	}
+
+	rendered := make([]jsonRecord, 0, len(records))
+
+	for _, rec := range records {
+		fields := make(map[string]any, len(rec.Fields))
+		for _, field := range rec.Fields {
+			fields[field.Key] = field.Value
+		}
+
+		rendered = append(rendered, jsonRecord{
+			Time:    rec.Time,
+			Level:   rec.Level.String(),
+			Message: rec.Message,
+			Logger:  rec.LoggerName,
+			Fields:  fields,
+		})
+	}
+
+	writer.Header().Set("Content-Type", "application/json")
+
+	if err := json.NewEncoder(writer).Encode(rendered); err != nil {
+		handler.handleError(fmt.Errorf("failed to encode records: %w", err))
+	}
+}
+
+// `parseRecordQuery` reads a `RecordQuery` from the query parameters of the request.
+func parseRecordQuery(request *http.Request) (RecordQuery, error) {
+	parameters := request.URL.Query()
+	query := RecordQuery{Contains: parameters.Get("contains")}
+
+	var err error
+
+	if level := parameters.Get("level"); level != "" {
+		if query.MinLevel, err = levels.Parse(level); err != nil {
+			return query, err
+		}
+	}
+
+	if since := parameters.Get("since"); since != "" {
+		if query.Since, err = time.Parse(time.RFC3339, since); err != nil {
+			return query, fmt.Errorf("invalid since: %w", err)
+		}
+	}
+
+	if until := parameters.Get("until"); until != "" {
+		if query.Until, err = time.Parse(time.RFC3339, until); err != nil {
+			return query, fmt.Errorf("invalid until: %w", err)
+		}
+	}
+
+	if limit := parameters.Get("limit"); limit != "" {
+		if query.Limit, err = strconv.Atoi(limit); err != nil {
+			return query, fmt.Errorf("invalid limit: %w", err)
+		}
+	}
+
+	return query, nil
+}
@@ -11,13 +11,26 @@ import (
 	"time"
 
 	"github.com/ZertyCraft/GoLogger/levels"
+	"github.com/ZertyCraft/GoLogger/record"
 )
 
+// `RotateHook` is a function called around a rotation with the path of the active file
+// and the path it is renamed to.
+type RotateHook func(oldPath string, newPath string)
+
+// `RotatingFileHandler` is a `StreamHandler` renaming the file once it exceeds the maximum file size
+// and keeping a limited number of backups. The active file is always `logDirectory/fileName`,
+// and a symlink pointing at it can be maintained, so tailing tools can follow a stable path.
 type RotatingFileHandler struct {
 	StreamHandler
-	maxFileSize    int    // The maximum size of the file before it is rotated in bytes
-	maxBackupCount int    // The number of backup files to keep
-	filenameFormat string // The format of the filename
+	maxFileSize    int        // The maximum size of the file before it is rotated in bytes
+	maxBackupCount int        // The number of backup files to keep
+	filenameFormat string     // The format of the filename
+	symlinkName    string     // The name of the symlink pointing at the active file (disabled if empty)
+	symlinkCreated bool       // Whether the symlink points at the active file
+	preRotateHook  RotateHook // Called before the active file is renamed
+	postRotateHook RotateHook // Called after the active file is renamed and a new one is opened
+	rotating       bool       // Whether a rotation waits for its pre-rotation hook, so that no other one starts
 }
 
 const (
@@ -36,6 +49,11 @@ func NewRotatingFileHandler() *RotatingFileHandler {
 		maxFileSize:    defaultMaxFileSize,
 		maxBackupCount: defaultMaxBackupCount,
 		filenameFormat: defaultFilenameFormat,
+		symlinkName:    "",
+		symlinkCreated: false,
+		preRotateHook:  nil,
+		postRotateHook: nil,
+		rotating:       false,
 	}
 }
 
@@ -62,6 +80,32 @@ func (handler *RotatingFileHandler) SetFilenameFormat(filenameFormat string) {
 	handler.filenameFormat = filenameFormat
 }
 
+// `SetSymlinkName` sets the name of a symlink, created in the log directory,
+// that always points at the active log file (e.g. "app.current").
+// An empty name disables the symlink.
+func (handler *RotatingFileHandler) SetSymlinkName(symlinkName string) {
+	// Acquire the lock
+	if handler.useLock {
+		handler.mutex.Lock()
+		defer handler.mutex.Unlock()
+	}
+
+	handler.symlinkName = symlinkName
+	handler.symlinkCreated = false
+}
+
+// `SetPreRotateHook` sets the hook called before the active file is renamed.
+// Hooks are called without holding the lock of the handler, so they can log with it.
+func (handler *RotatingFileHandler) SetPreRotateHook(hook RotateHook) {
+	handler.preRotateHook = hook
+}
+
+// `SetPostRotateHook` sets the hook called once the active file is renamed and a new one is opened.
+// The renamed file is complete at this point, so it can e.g. be uploaded to an archival storage.
+func (handler *RotatingFileHandler) SetPostRotateHook(hook RotateHook) {
+	handler.postRotateHook = hook
+}
+
 // ======== Getters ========
 // `GetMaxFileSize` returns the value of the `maxFileSize` field of the `RotatingFileHandler`.
 func (handler *RotatingFileHandler) GetMaxFileSize() int {
@@ -78,6 +122,11 @@ func (handler *RotatingFileHandler) GetFilenameFormat() string {
 	return handler.filenameFormat
 }
 
+// `GetSymlinkName` returns the value of the `symlinkName` field of the `RotatingFileHandler`.
+func (handler *RotatingFileHandler) GetSymlinkName() string {
+	return handler.symlinkName
+}
+
 // ======== Methods ========
 
 // `getNewFileName` returns the new filename from file name format placeholders.
@@ -121,18 +170,11 @@ func (handler *RotatingFileHandler) deleteFile(directory string, name string) er
 	return nil
 }
 
-// `rotate` rotates the file.
-// It renames the file to the new filename
-// and creates a new file with the original name.
-// Then it deletes the old backup files.
-func (handler *RotatingFileHandler) rotate() {
-	// Close the file
-	handler.close()
-
-	// Get new file name
+// `backupPaths` returns the path of the active file and the path of its next backup, without acquiring the lock.
+func (handler *RotatingFileHandler) backupPaths() (string, string) {
 	newFileName := handler.getNewFileName(handler.fileName, 1)
 
-	// If the file already exists, rename it
+	// If the file already exists, use the next backup number
 	if _, err := os.Stat(filepath.Join(handler.logDirectory, newFileName)); err == nil {
 		for i := 1; ; i++ {
 			newFileName = handler.getNewFileName(handler.fileName, i)
@@ -142,8 +184,18 @@ func (handler *RotatingFileHandler) rotate() {
 		}
 	}
 
+	return filepath.Join(handler.logDirectory, handler.fileName), filepath.Join(handler.logDirectory, newFileName)
+}
+
+// `rotate` rotates the file.
+// It renames the file to the new path
+// and creates a new file with the original name.
+func (handler *RotatingFileHandler) rotate(newPath string) {
+	// Close the file
+	handler.close()
+
 	// Rename the file
-	if err := handler.renameFile(handler.logDirectory, handler.fileName, newFileName); err != nil {
+	if err := handler.renameFile(handler.logDirectory, handler.fileName, filepath.Base(newPath)); err != nil {
 		panic(err)
 	}
 
@@ -153,6 +205,34 @@ func (handler *RotatingFileHandler) rotate() {
 	}
 }
 
+// `ensureSymlink` points the symlink at the active file without acquiring the lock, if a symlink name is set.
+// The symlink is created under a temporary name then renamed, so readers never see it missing.
+func (handler *RotatingFileHandler) ensureSymlink() {
+	if handler.symlinkName == "" || handler.symlinkCreated {
+		return
+	}
+
+	symlinkPath := filepath.Join(handler.logDirectory, handler.symlinkName)
+	temporaryPath := symlinkPath + ".tmp"
+
+	// Remove a leftover temporary symlink
+	_ = os.Remove(temporaryPath)
+
+	if err := os.Symlink(handler.fileName, temporaryPath); err != nil {
+		handler.handleError(fmt.Errorf("failed to create symlink: %w", err))
+
+		return
+	}
+
+	if err := os.Rename(temporaryPath, symlinkPath); err != nil {
+		handler.handleError(fmt.Errorf("failed to create symlink: %w", err))
+
+		return
+	}
+
+	handler.symlinkCreated = true
+}
+
 // `getFileSize` returns the size of the file in bytes.
 func (handler *RotatingFileHandler) getFileSize() int {
 	fileInfo, err := os.Stat(filepath.Join(handler.logDirectory, handler.fileName))
@@ -179,26 +259,143 @@ func (handler *RotatingFileHandler) sortBackupFiles(backupFiles []string) {
 
 // `Log` logs the given message using the handler.
 func (handler *RotatingFileHandler) Log(level levels.Level, message string) {
-	if !handler.isLevelSufficient(level) {
+	handler.LogRecord(record.New(level, message))
+}
+
+// `LogRecord` logs the given record using the handler, like `Log`.
+func (handler *RotatingFileHandler) LogRecord(rec record.Record) {
+	if !handler.shouldLog(rec) {
 		return
 	}
 
+	if !handler.rotateIfNeeded() {
+		return
+	}
+
+	// Log the message using the stream handler
+	handler.StreamHandler.LogRecord(rec)
+}
+
+// Opens the log file and rotates it if necessary, calling the hooks around the rotation.
+// The checks and the rotation hold the lock, so that the background flusher and watchers never use the file
+// while it is rotated, but the hooks are called without it, so that they can log with the handler.
+// It returns false if the handler is closed.
+func (handler *RotatingFileHandler) rotateIfNeeded() bool {
+	oldPath, newPath, needed, ok := handler.checkRotation()
+	if !needed {
+		return ok
+	}
+
+	if handler.preRotateHook != nil {
+		handler.preRotateHook(oldPath, newPath)
+	}
+
+	rotated, ok := handler.finishRotation(newPath)
+
+	if rotated && handler.postRotateHook != nil {
+		handler.postRotateHook(oldPath, newPath)
+	}
+
+	return ok
+}
+
+// `checkRotation` opens the log file and checks if it must be rotated, returning the paths of the rotation if so.
+// Old backups are cleaned up when no rotation is needed. It returns false as last value if the handler is closed.
+func (handler *RotatingFileHandler) checkRotation() (string, string, bool, bool) {
+	// Acquire the lock
+	if handler.useLock {
+		handler.mutex.Lock()
+		defer handler.mutex.Unlock()
+	}
+
+	if handler.closed.Load() {
+		handler.handleError(ErrClosed)
+
+		return "", "", false, false
+	}
+
 	handler.ensureFileOpened()
+	handler.ensureSymlink()
 
-	handler.checkAndRotateFile()
+	// Messages logged by the pre-rotation hook are written to the file about to be rotated
+	if handler.rotating {
+		return "", "", false, true
+	}
 
-	handler.cleanupOldBackups()
+	if handler.useFileLock {
+		if err := handler.acquireFileLock(); err != nil {
+			handler.handleError(err)
 
-	// Log the message using the stream handler
-	handler.StreamHandler.Log(level, message)
+			return "", "", false, true
+		}
+		defer handler.releaseFileLock()
+
+		// Reopen the file if another process rotated it
+		if handler.isMoved() {
+			if err := handler.reopen(); err != nil {
+				handler.handleError(err)
+
+				return "", "", false, true
+			}
+		}
+	}
+
+	if handler.getFileSize() <= handler.maxFileSize {
+		handler.cleanupOldBackups()
+
+		return "", "", false, true
+	}
+
+	handler.rotating = true
+	oldPath, newPath := handler.backupPaths()
+
+	return oldPath, newPath, true, true
 }
 
-// Checks the file size and rotates the log file if necessary.
-func (handler *RotatingFileHandler) checkAndRotateFile() {
-	if handler.getFileSize() > handler.maxFileSize {
-		log.Println("Rotating file (size =", handler.getFileSize(), ")")
-		handler.rotate()
+// `finishRotation` renames the log file to `newPath`, opens a new one and cleans up old backups.
+// It returns whether the file was rotated, and false as last value if the handler is closed.
+func (handler *RotatingFileHandler) finishRotation(newPath string) (bool, bool) {
+	// Acquire the lock
+	if handler.useLock {
+		handler.mutex.Lock()
+		defer handler.mutex.Unlock()
+	}
+
+	handler.rotating = false
+
+	if handler.closed.Load() {
+		handler.handleError(ErrClosed)
+
+		return false, false
 	}
+
+	if handler.useFileLock {
+		if err := handler.acquireFileLock(); err != nil {
+			handler.handleError(err)
+
+			return false, true
+		}
+		defer handler.releaseFileLock()
+
+		// Another process rotated the file while the hook was running
+		if handler.isMoved() {
+			if err := handler.reopen(); err != nil {
+				handler.handleError(err)
+			}
+
+			return false, true
+		}
+	}
+
+	log.Println("Rotating file (size =", handler.getFileSize(), ")")
+	handler.rotate(newPath)
+
+	handler.symlinkCreated = false
+	handler.ensureSymlink()
+
+	handler.cleanupOldBackups()
+
+	return true, true
 }
 
 // Cleans up old backup files exceeding the maximum backup count.
@@ -211,6 +408,11 @@ func (handler *RotatingFileHandler) cleanupOldBackups() {
 	backupFiles := make([]string, 0)
 
 	for _, file := range files {
+		if file.Name() == handler.symlinkName || file.Name() == handler.symlinkName+".tmp" ||
+			file.Name() == handler.fileName+lockFileSuffix {
+			continue
+		}
+
 		if strings.HasPrefix(file.Name(), handler.fileName+".") {
 			backupFiles = append(backupFiles, file.Name())
 		}
@@ -0,0 +1,323 @@
+package handler
+
+import (
+	"fmt"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ZertyCraft/GoLogger/formater"
+	"github.com/ZertyCraft/GoLogger/levels"
+	"github.com/ZertyCraft/GoLogger/record"
+)
+
+// `SMTPHandler` is a handler that emails records at or above the trigger level, e.g. to alert on-call.
+// The records triggering within the batch window are sent in one email, with the last records below
+// the trigger level logged before the first of them as context. At most one email is sent per interval:
+// records triggering meanwhile are batched in the next email.
+type SMTPHandler struct {
+	BaseHandler
+	address      string // The address of the SMTP server, as "host:port"
+	auth         smtp.Auth
+	from         string
+	to           []string
+	subject      string          // The prefix of the subject of the emails
+	triggerLevel levels.Level    // The level from which records are emailed
+	window       time.Duration   // The time waited after the first record of an email before sending it
+	interval     time.Duration   // The minimum time between two emails
+	contextSize  int             // The number of records below the trigger level included in the emails
+	maxRecords   int             // The maximum number of records of an email, the next ones are only counted
+	context      []record.Record // The last records below the trigger level
+	pending      []record.Record // The records of the next email
+	pendingCtx   []record.Record // The context of the next email
+	omitted      int             // The number of records of the next email beyond `maxRecords`
+	lastSent     time.Time
+	generation   uint64 // The number of emails started, to ignore the timers of emails already sent
+	timer        *time.Timer
+	closed       bool
+	mutex        sync.Mutex
+}
+
+const (
+	// `defaultSMTPSubject` is the default value for the `subject` field of the `SMTPHandler`.
+	defaultSMTPSubject = "Log alert"
+	// `defaultSMTPWindow` is the default value for the `window` field of the `SMTPHandler`.
+	defaultSMTPWindow = 10 * time.Second
+	// `defaultSMTPInterval` is the default value for the `interval` field of the `SMTPHandler`.
+	defaultSMTPInterval = 5 * time.Minute
+	// `defaultSMTPContextSize` is the default value for the `contextSize` field of the `SMTPHandler`.
+	defaultSMTPContextSize = 20
+	// `defaultSMTPMaxRecords` is the default value for the `maxRecords` field of the `SMTPHandler`.
+	defaultSMTPMaxRecords = 100
+)
+
+// `NewSMTPHandler` returns a new `SMTPHandler` emailing CRITICAL records from `from` to `to`
+// through the SMTP server at `address` ("host:port"). The default formater is a `LineFormater`.
+// The level of the `SMTPHandler` is DEBUG, so that the records below the trigger level are kept as context.
+func NewSMTPHandler(address string, from string, to ...string) *SMTPHandler {
+	handler := &SMTPHandler{
+		BaseHandler:  *NewBaseHandler(),
+		address:      address,
+		auth:         nil,
+		from:         from,
+		to:           to,
+		subject:      defaultSMTPSubject,
+		triggerLevel: levels.CRITICAL,
+		window:       defaultSMTPWindow,
+		interval:     defaultSMTPInterval,
+		contextSize:  defaultSMTPContextSize,
+		maxRecords:   defaultSMTPMaxRecords,
+		context:      make([]record.Record, 0),
+		pending:      nil,
+		pendingCtx:   nil,
+		omitted:      0,
+		lastSent:     time.Time{},
+		generation:   0,
+		timer:        nil,
+		closed:       false,
+		mutex:        sync.Mutex{},
+	}
+	handler.Level = levels.DEBUG
+	handler.formater = formater.NewLineFormater()
+
+	return handler
+}
+
+// ======== Setters ========
+// `SetAuth` sets the authentication used with the SMTP server, e.g. `smtp.PlainAuth`.
+func (handler *SMTPHandler) SetAuth(auth smtp.Auth) {
+	handler.auth = auth
+}
+
+// `SetSubject` sets the prefix of the subject of the emails, followed by the first record of the email.
+func (handler *SMTPHandler) SetSubject(subject string) {
+	handler.subject = subject
+}
+
+// `SetTriggerLevel` sets the value of the `triggerLevel` field of the `SMTPHandler`.
+func (handler *SMTPHandler) SetTriggerLevel(triggerLevel levels.Level) {
+	handler.mutex.Lock()
+	defer handler.mutex.Unlock()
+
+	handler.triggerLevel = triggerLevel
+}
+
+// `SetWindow` sets the time waited after the first record of an email before sending it.
+func (handler *SMTPHandler) SetWindow(window time.Duration) {
+	handler.mutex.Lock()
+	defer handler.mutex.Unlock()
+
+	handler.window = window
+}
+
+// `SetInterval` sets the minimum time between two emails.
+func (handler *SMTPHandler) SetInterval(interval time.Duration) {
+	handler.mutex.Lock()
+	defer handler.mutex.Unlock()
+
+	handler.interval = interval
+}
+
+// `SetContextSize` sets the number of records below the trigger level included in the emails.
+func (handler *SMTPHandler) SetContextSize(contextSize int) {
+	handler.mutex.Lock()
+	defer handler.mutex.Unlock()
+
+	handler.contextSize = contextSize
+}
+
+// `SetMaxRecords` sets the maximum number of records of an email. The records beyond it are only counted.
+// Values below 1 are set to 1, so that an email always shows the record that triggered it.
+func (handler *SMTPHandler) SetMaxRecords(maxRecords int) {
+	handler.mutex.Lock()
+	defer handler.mutex.Unlock()
+
+	handler.maxRecords = max(maxRecords, 1)
+}
+
+// ======== Getters ========
+// `GetTriggerLevel` returns the value of the `triggerLevel` field of the `SMTPHandler`.
+func (handler *SMTPHandler) GetTriggerLevel() levels.Level {
+	handler.mutex.Lock()
+	defer handler.mutex.Unlock()
+
+	return handler.triggerLevel
+}
+
+// `GetWindow` returns the value of the `window` field of the `SMTPHandler`.
+func (handler *SMTPHandler) GetWindow() time.Duration {
+	handler.mutex.Lock()
+	defer handler.mutex.Unlock()
+
+	return handler.window
+}
+
+// `GetInterval` returns the value of the `interval` field of the `SMTPHandler`.
+func (handler *SMTPHandler) GetInterval() time.Duration {
+	handler.mutex.Lock()
+	defer handler.mutex.Unlock()
+
+	return handler.interval
+}
+
+// ======== Methods ========
+// `Log` emails the message if its level is at or above the trigger level, or keeps it as context otherwise.
+func (handler *SMTPHandler) Log(level levels.Level, message string) {
+	handler.LogRecord(record.New(level, message))
+}
+
+// `LogRecord` adds the record to the next email if its level is at or above the trigger level,
+// or keeps it as context otherwise.
+func (handler *SMTPHandler) LogRecord(rec record.Record) {
+	if !handler.shouldLog(rec) {
+		return
+	}
+
+	handler.mutex.Lock()
+	defer handler.mutex.Unlock()
+
+	if handler.closed {
+		handler.handleError(ErrClosed)
+
+		return
+	}
+
+	if rec.Level < handler.triggerLevel {
+		if handler.contextSize > 0 {
+			if len(handler.context) >= handler.contextSize {
+				handler.context = handler.context[len(handler.context)-handler.contextSize+1:]
+			}
+
+			handler.context = append(handler.context, rec)
+		}
+
+		return
+	}
+
+	if handler.pending == nil {
+		handler.pending = make([]record.Record, 0)
+		handler.pendingCtx = handler.context
+		handler.context = make([]record.Record, 0)
+
+		sendAt := time.Now().Add(handler.window)
+		if next := handler.lastSent.Add(handler.interval); next.After(sendAt) {
+			sendAt = next
+		}
+
+		handler.generation++
+		generation := handler.generation
+
+		handler.timer = time.AfterFunc(time.Until(sendAt), func() {
+			if err := handler.send(generation); err != nil {
+				handler.handleError(err)
+			}
+		})
+	}
+
+	if len(handler.pending) >= handler.maxRecords {
+		handler.omitted++
+
+		return
+	}
+
+	handler.pending = append(handler.pending, rec)
+}
+
+// `send` sends the next email, if any, and if it is the given one (any if 0).
+func (handler *SMTPHandler) send(generation uint64) error {
+	handler.mutex.Lock()
+
+	if handler.pending == nil || (generation != 0 && generation != handler.generation) {
+		handler.mutex.Unlock()
+
+		return nil
+	}
+
+	if handler.timer != nil {
+		handler.timer.Stop()
+		handler.timer = nil
+	}
+
+	records, context, omitted := handler.pending, handler.pendingCtx, handler.omitted
+	handler.pending, handler.pendingCtx, handler.omitted = nil, nil, 0
+	handler.lastSent = time.Now()
+	handler.mutex.Unlock()
+
+	message := handler.message(records, context, omitted)
+
+	if err := smtp.SendMail(handler.address, handler.auth, handler.from, handler.to, message); err != nil {
+		return fmt.Errorf("failed to send email with %d records: %w", len(records)+omitted, err)
+	}
+
+	return nil
+}
+
+// `message` returns the email of the records and their context.
+func (handler *SMTPHandler) message(records []record.Record, context []record.Record, omitted int) []byte {
+	subject := handler.subject + ": " + records[0].Level.String() + " " + records[0].Message
+	subject = strings.Join(strings.Fields(subject), " ")
+
+	if more := len(records) - 1 + omitted; more > 0 {
+		subject += " (+" + strconv.Itoa(more) + " more)"
+	}
+
+	var builder strings.Builder
+
+	builder.WriteString("From: " + handler.from + "\r\n")
+	builder.WriteString("To: " + strings.Join(handler.to, ", ") + "\r\n")
+	builder.WriteString("Subject: " + subject + "\r\n")
+	builder.WriteString("Date: " + time.Now().Format(time.RFC1123Z) + "\r\n")
+	builder.WriteString("MIME-Version: 1.0\r\n")
+	builder.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	builder.WriteString("\r\n")
+
+	handler.writeRecords(&builder, records)
+
+	if omitted > 0 {
+		builder.WriteString("... and " + strconv.Itoa(omitted) + " more records\r\n")
+	}
+
+	if len(context) > 0 {
+		builder.WriteString("\r\nContext:\r\n")
+		handler.writeRecords(&builder, context)
+	}
+
+	return []byte(builder.String())
+}
+
+// `writeRecords` writes the formatted records, one per line.
+func (handler *SMTPHandler) writeRecords(builder *strings.Builder, records []record.Record) {
+	for _, rec := range records {
+		line, err := formater.FormatRecord(handler.formater, rec)
+		if err != nil {
+			handler.handleError(fmt.Errorf("failed to format message: %w", err))
+
+			continue
+		}
+
+		builder.WriteString(strings.ReplaceAll(strings.TrimSuffix(line, "\n"), "\n", "\r\n") + "\r\n")
+	}
+}
+
+// `Flush` sends the next email now, without waiting for the window or the interval.
+func (handler *SMTPHandler) Flush() error {
+	return handler.send(0)
+}
+
+// `Close` sends the next email, if any, and stops accepting records. Closing an already closed handler does nothing.
+func (handler *SMTPHandler) Close() error {
+	handler.mutex.Lock()
+
+	if handler.closed {
+		handler.mutex.Unlock()
+
+		return nil
+	}
+
+	handler.closed = true
+	handler.mutex.Unlock()
+
+	return handler.send(0)
+}
@@ -0,0 +1,204 @@
+package handler
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ZertyCraft/GoLogger/levels"
+	"github.com/ZertyCraft/GoLogger/record"
+)
+
+// `samplingCounter` counts the records of a key during the current interval.
+type samplingCounter struct {
+	count      int           // The number of records received
+	suppressed int           // The number of records not logged
+	last       record.Record // The last record received, used to build the summary
+}
+
+// `SamplingHandler` is a handler that caps repeated records logged with another handler.
+// During each interval, the first records of a key are logged, then only every Mth one.
+// At the end of the interval, a summary record tells how many records of the key were suppressed.
+// By default, the key of a record is its level and message.
+type SamplingHandler struct {
+	BaseHandler
+	target     Handler
+	first      int                            // The number of records of a key logged per interval
+	thereafter int                            // Log every Mth record after the first ones (none if 0)
+	interval   time.Duration                  // The duration of an interval
+	keyFunc    func(rec record.Record) string // Returns the key of a record
+	counters   map[string]*samplingCounter    // The counters of the current interval by key
+	mutex      sync.Mutex
+	stop       chan struct{}
+	done       chan struct{}
+	closed     bool
+}
+
+// `defaultSamplingInterval` is the interval of the `SamplingHandler` when the given one is not positive.
+const defaultSamplingInterval = time.Second
+
+// `defaultSamplingKey` returns the level and the message of the record.
+func defaultSamplingKey(rec record.Record) string {
+	return rec.Level.String() + " " + rec.Message
+}
+
+// `NewSamplingHandler` returns a new `SamplingHandler` logging with `target`, per key and per interval,
+// the `first` records then every `thereafter`th record.
+// The level of the `SamplingHandler` is DEBUG, so that filtering is left to the target.
+// If the interval is not positive, `defaultSamplingInterval` is used.
+func NewSamplingHandler(target Handler, interval time.Duration, first int, thereafter int) *SamplingHandler {
+	if interval <= 0 {
+		interval = defaultSamplingInterval
+	}
+
+	handler := &SamplingHandler{
+		BaseHandler: *NewBaseHandler(),
+		target:      target,
+		first:       first,
+		thereafter:  thereafter,
+		interval:    interval,
+		keyFunc:     defaultSamplingKey,
+		counters:    make(map[string]*samplingCounter),
+		mutex:       sync.Mutex{},
+		stop:        make(chan struct{}),
+		done:        make(chan struct{}),
+		closed:      false,
+	}
+	handler.Level = levels.DEBUG
+
+	go handler.run()
+
+	return handler
+}
+
+// ======== Setters ========
+// `SetKeyFunc` sets the function returning the key of a record,
+// e.g. to group messages built from the same template under a field value.
+func (handler *SamplingHandler) SetKeyFunc(keyFunc func(rec record.Record) string) {
+	handler.mutex.Lock()
+	defer handler.mutex.Unlock()
+
+	handler.keyFunc = keyFunc
+}
+
+// ======== Getters ========
+// `GetTarget` returns the handler the records are logged with.
+func (handler *SamplingHandler) GetTarget() Handler {
+	return handler.target
+}
+
+// ======== Methods ========
+// `run` ends an interval every `interval` until the handler is closed.
+func (handler *SamplingHandler) run() {
+	defer close(handler.done)
+
+	ticker := time.NewTicker(handler.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-handler.stop:
+			return
+		case <-ticker.C:
+			handler.endInterval()
+		}
+	}
+}
+
+// `endInterval` resets the counters and logs a summary for each key with suppressed records.
+func (handler *SamplingHandler) endInterval() {
+	handler.mutex.Lock()
+	counters := handler.counters
+	handler.counters = make(map[string]*samplingCounter)
+	handler.mutex.Unlock()
+
+	for key, counter := range counters {
+		if counter.suppressed == 0 {
+			continue
+		}
+
+		summary := record.New(counter.last.Level, fmt.Sprintf(
+			"%d similar messages suppressed in the last %s: %s", counter.suppressed, handler.interval, counter.last.Message,
+		))
+		summary.LoggerName = counter.last.LoggerName
+		summary.Fields = []record.Field{
+			{Key: "sampling_key", Value: key},
+			{Key: "sampling_suppressed", Value: counter.suppressed},
+		}
+
+		LogRecord(handler.target, summary)
+	}
+}
+
+// `Log` logs the message with the target if it is sampled.
+func (handler *SamplingHandler) Log(level levels.Level, message string) {
+	handler.LogRecord(record.New(level, message))
+}
+
+// `LogRecord` logs the record with the target if it is among the first ones of its key in the interval,
+// or if it is an Mth one after them.
+func (handler *SamplingHandler) LogRecord(rec record.Record) {
+	if !handler.shouldLog(rec) {
+		return
+	}
+
+	handler.mutex.Lock()
+
+	if handler.closed {
+		handler.mutex.Unlock()
+		handler.handleError(ErrClosed)
+
+		return
+	}
+
+	key := handler.keyFunc(rec)
+
+	counter, ok := handler.counters[key]
+	if !ok {
+		counter = &samplingCounter{count: 0, suppressed: 0, last: rec}
+		handler.counters[key] = counter
+	}
+
+	counter.count++
+	counter.last = rec
+
+	sampled := counter.count <= handler.first ||
+		(handler.thereafter > 0 && (counter.count-handler.first)%handler.thereafter == 0)
+	if !sampled {
+		counter.suppressed++
+	}
+
+	handler.mutex.Unlock()
+
+	if sampled {
+		LogRecord(handler.target, rec)
+	}
+}
+
+// `Flush` flushes the target if it implements `Flusher`.
+func (handler *SamplingHandler) Flush() error {
+	return flushHandler(handler.target)
+}
+
+// `Close` stops the intervals, logs the summaries of the current one,
+// then closes the target if it implements `Closer`, or flushes it otherwise.
+// Closing an already closed handler does nothing.
+func (handler *SamplingHandler) Close() error {
+	handler.mutex.Lock()
+
+	if handler.closed {
+		handler.mutex.Unlock()
+
+		return nil
+	}
+
+	handler.closed = true
+	handler.mutex.Unlock()
+
+	close(handler.stop)
+	<-handler.done
+
+	handler.endInterval()
+
+	return closeHandler(handler.target)
+}
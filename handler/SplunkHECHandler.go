@@ -0,0 +1,499 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ZertyCraft/GoLogger/formater"
+	"github.com/ZertyCraft/GoLogger/record"
+)
+
+// `SplunkHECHandler` is a handler that sends records to a Splunk HTTP Event Collector.
+// It is an `HTTPHandler` wrapping each record in the HEC event envelope: the time in epoch seconds,
+// the host, source, sourcetype and index, the record formatted with the formater of the handler
+// (a `JSONFormater` by default) as event, and its fields as indexed fields.
+// If an acknowledgement channel is set, the acknowledgements of the sent batches are queried in the background,
+// so that waiting for them does not delay the next batches, and the batches not acknowledged before
+// the acknowledgement timeout are queued again.
+type SplunkHECHandler struct {
+	*HTTPHandler
+	baseURL         string // The URL of the collector, without the endpoint path
+	host            string
+	source          string
+	sourceType      string
+	index           string
+	ackChannel      string             // The channel of the acknowledgements, disabled if empty
+	ackTimeout      time.Duration      // The maximum time waited for the acknowledgement of a batch
+	ackPollInterval time.Duration      // The time between two acknowledgement queries
+	acks            map[int64]hecBatch // The batches waiting for their acknowledgement by ack id
+	ackMutex        sync.Mutex
+	ackUpdate       chan struct{} // Signals `Flush` that acknowledgements were received or timed out
+	ackStop         chan struct{}
+	ackDone         chan struct{}
+	ackOnce         sync.Once
+}
+
+// `hecBatch` is a batch sent to the collector and waiting for its acknowledgement.
+type hecBatch struct {
+	items []httpItem
+	sent  time.Time
+}
+
+const (
+	// `defaultHECAckTimeout` is the default value for the `ackTimeout` field of the `SplunkHECHandler`.
+	defaultHECAckTimeout = time.Minute
+	// `defaultHECAckPollInterval` is the default value for the `ackPollInterval` field of the `SplunkHECHandler`.
+	defaultHECAckPollInterval = time.Second
+)
+
+// `errNotAcknowledged` is returned when a batch was not acknowledged before the acknowledgement timeout.
+var errNotAcknowledged = errors.New("batch not acknowledged")
+
+// `hecEvent` is the HEC envelope of a record.
+type hecEvent struct {
+	Time       json.Number       `json:"time"`
+	Host       string            `json:"host,omitempty"`
+	Source     string            `json:"source,omitempty"`
+	SourceType string            `json:"sourcetype,omitempty"`
+	Index      string            `json:"index,omitempty"`
+	Event      any               `json:"event"`
+	Fields     map[string]string `json:"fields,omitempty"`
+}
+
+// `hecResponse` is the response of the collector to a batch.
+type hecResponse struct {
+	Text  string `json:"text"`
+	Code  int    `json:"code"`
+	AckID *int64 `json:"ackId"`
+}
+
+// `NewSplunkHECHandler` returns a new `SplunkHECHandler` sending events to the collector at `url`
+// (e.g. "https://splunk:8088") authenticated with `token`, from the local host name.
+func NewSplunkHECHandler(url string, token string) *SplunkHECHandler {
+	baseURL := strings.TrimSuffix(url, "/")
+
+	host, err := os.Hostname()
+	if err != nil {
+		host = ""
+	}
+
+	handler := &SplunkHECHandler{
+		HTTPHandler:     NewHTTPHandler(baseURL + "/services/collector/event"),
+		baseURL:         baseURL,
+		host:            host,
+		source:          "",
+		sourceType:      "",
+		index:           "",
+		ackChannel:      "",
+		ackTimeout:      defaultHECAckTimeout,
+		ackPollInterval: defaultHECAckPollInterval,
+		acks:            make(map[int64]hecBatch),
+		ackMutex:        sync.Mutex{},
+		ackUpdate:       make(chan struct{}, 1),
+		ackStop:         make(chan struct{}),
+		ackDone:         make(chan struct{}),
+		ackOnce:         sync.Once{},
+	}
+	handler.encoding = HTTPEncodingNDJSON
+	handler.encodeRecord = handler.envelope
+	handler.checkResponse = handler.checkHECResponse
+	handler.SetHeader("Authorization", "Splunk "+token)
+
+	return handler
+}
+
+// ======== Setters ========
+// `SetHost` sets the host of the events.
+func (handler *SplunkHECHandler) SetHost(host string) {
+	handler.host = host
+}
+
+// `SetSource` sets the source of the events. The collector default is used if empty.
+func (handler *SplunkHECHandler) SetSource(source string) {
+	handler.source = source
+}
+
+// `SetSourceType` sets the sourcetype of the events. The collector default is used if empty.
+func (handler *SplunkHECHandler) SetSourceType(sourceType string) {
+	handler.sourceType = sourceType
+}
+
+// `SetIndex` sets the index of the events. The collector default is used if empty.
+func (handler *SplunkHECHandler) SetIndex(index string) {
+	handler.index = index
+}
+
+// `SetAckChannel` enables indexer acknowledgements on the channel (a GUID), so that each batch is queued again
+// if the collector does not acknowledge it, or disables them if empty.
+func (handler *SplunkHECHandler) SetAckChannel(ackChannel string) {
+	handler.ackChannel = ackChannel
+
+	if ackChannel == "" {
+		handler.headers.Del("X-Splunk-Request-Channel")
+	} else {
+		handler.SetHeader("X-Splunk-Request-Channel", ackChannel)
+	}
+}
+
+// `SetAckTimeout` sets the maximum time waited for the acknowledgement of a batch before queuing it again,
+// and the time between two acknowledgement queries. Batches are queued again at most `maxRetries` times.
+func (handler *SplunkHECHandler) SetAckTimeout(ackTimeout time.Duration, pollInterval time.Duration) {
+	handler.ackTimeout = ackTimeout
+	handler.ackPollInterval = pollInterval
+}
+
+// ======== Getters ========
+// `GetHost` returns the host of the events.
+func (handler *SplunkHECHandler) GetHost() string {
+	return handler.host
+}
+
+// `GetSource` returns the source of the events.
+func (handler *SplunkHECHandler) GetSource() string {
+	return handler.source
+}
+
+// `GetSourceType` returns the sourcetype of the events.
+func (handler *SplunkHECHandler) GetSourceType() string {
+	return handler.sourceType
+}
+
+// `GetIndex` returns the index of the events.
+func (handler *SplunkHECHandler) GetIndex() string {
+	return handler.index
+}
+
+// `GetAckChannel` returns the channel of the acknowledgements.
+func (handler *SplunkHECHandler) GetAckChannel() string {
+	return handler.ackChannel
+}
+
+// `Unacknowledged` returns the number of sent records waiting for their acknowledgement.
+func (handler *SplunkHECHandler) Unacknowledged() int {
+	handler.ackMutex.Lock()
+	defer handler.ackMutex.Unlock()
+
+	count := 0
+	for _, batch := range handler.acks {
+		count += len(batch.items)
+	}
+
+	return count
+}
+
+// ======== Methods ========
+// `envelope` returns the HEC event of the record. The formatted record is sent as a JSON value if it is one,
+// and as a string otherwise.
+func (handler *SplunkHECHandler) envelope(rec record.Record) ([]byte, error) {
+	formatted, err := handler.formatRecord(rec)
+	if err != nil {
+		return nil, err
+	}
+
+	var event any = string(formatted)
+	if json.Valid(formatted) {
+		event = json.RawMessage(formatted)
+	}
+
+	var fields map[string]string
+
+	if len(rec.Fields) > 0 {
+		fields = make(map[string]string, len(rec.Fields))
+
+		for _, field := range rec.Fields {
+			fields[field.Key] = fmt.Sprint(formater.JSONValue(field.Value))
+		}
+	}
+
+	microseconds := rec.Time.UnixMicro()
+
+	data, err := json.Marshal(hecEvent{
+		Time:       json.Number(fmt.Sprintf("%d.%06d", microseconds/1e6, microseconds%1e6)),
+		Host:       handler.host,
+		Source:     handler.source,
+		SourceType: handler.sourceType,
+		Index:      handler.index,
+		Event:      event,
+		Fields:     fields,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode event: %w", err)
+	}
+
+	return data, nil
+}
+
+// `checkHECResponse` checks the status of the response, then tracks the acknowledgement of the batch
+// if acknowledgements are enabled.
+func (handler *SplunkHECHandler) checkHECResponse(
+	response *http.Response, body []byte, items []httpItem,
+) ([]httpItem, error) {
+	retry, err := checkHTTPStatus(response, body, items)
+	if err != nil || handler.ackChannel == "" {
+		return retry, err
+	}
+
+	var decoded hecResponse
+	if err := json.Unmarshal(body, &decoded); err != nil || decoded.AckID == nil {
+		return nil, fmt.Errorf("no acknowledgement id in response: %s", strings.TrimSpace(string(body)))
+	}
+
+	handler.ackMutex.Lock()
+	handler.acks[*decoded.AckID] = hecBatch{items: items, sent: time.Now()}
+	handler.ackMutex.Unlock()
+
+	handler.ackOnce.Do(func() { go handler.pollAcks() })
+
+	return nil, nil
+}
+
+// `pollAcks` queries the pending acknowledgements every poll interval until the handler is closed.
+func (handler *SplunkHECHandler) pollAcks() {
+	defer close(handler.ackDone)
+
+	for {
+		timer := time.NewTimer(handler.ackPollInterval)
+
+		select {
+		case <-handler.ackStop:
+			timer.Stop()
+
+			return
+		case <-timer.C:
+		}
+
+		handler.checkAcks()
+	}
+}
+
+// `checkAcks` queries the pending acknowledgements, forgets the acknowledged batches,
+// and queues again the batches not acknowledged before the acknowledgement timeout.
+func (handler *SplunkHECHandler) checkAcks() {
+	handler.ackMutex.Lock()
+
+	ackIDs := make([]int64, 0, len(handler.acks))
+	for ackID := range handler.acks {
+		ackIDs = append(ackIDs, ackID)
+	}
+
+	handler.ackMutex.Unlock()
+
+	if len(ackIDs) == 0 {
+		return
+	}
+
+	acknowledged, err := handler.queryAcks(ackIDs)
+	if err != nil {
+		handler.handleError(err)
+	}
+
+	expired := make(map[int64]hecBatch)
+
+	handler.ackMutex.Lock()
+
+	for _, ackID := range ackIDs {
+		batch := handler.acks[ackID]
+
+		if acknowledged[ackID] {
+			delete(handler.acks, ackID)
+		} else if time.Since(batch.sent) >= handler.ackTimeout {
+			delete(handler.acks, ackID)
+			expired[ackID] = batch
+		}
+	}
+
+	handler.ackMutex.Unlock()
+
+	for ackID, batch := range expired {
+		handler.resend(ackID, batch.items)
+	}
+
+	notify(handler.ackUpdate)
+}
+
+// `resend` queues again the records of a batch that was not acknowledged,
+// dropping and reporting the records already queued again `maxRetries` times.
+func (handler *SplunkHECHandler) resend(ackID int64, items []httpItem) {
+	failed := 0
+
+	for _, item := range items {
+		if item.resent >= handler.maxRetries {
+			failed++
+
+			continue
+		}
+
+		item.resent++
+		handler.enqueue(item)
+	}
+
+	if failed > 0 {
+		handler.queue.dropped.Add(uint64(failed))
+		handler.handleError(fmt.Errorf(
+			"%w: %d records not sent to %s: %w: ack id %d", ErrDeliveryFailed, failed, handler.url, errNotAcknowledged, ackID,
+		))
+	}
+}
+
+// `queryAcks` asks the collector which acknowledgements are confirmed.
+func (handler *SplunkHECHandler) queryAcks(ackIDs []int64) (map[int64]bool, error) {
+	body, err := json.Marshal(map[string][]int64{"acks": ackIDs})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode acknowledgement query: %w", err)
+	}
+
+	request, err := http.NewRequest(http.MethodPost, handler.baseURL+"/services/collector/ack", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create acknowledgement query: %w", err)
+	}
+
+	for key, values := range handler.headers {
+		request.Header[key] = values
+	}
+
+	request.Header.Set("Content-Type", "application/json")
+
+	response, err := handler.client.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query acknowledgement: %w", err)
+	}
+	defer response.Body.Close()
+
+	responseBody, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read acknowledgement: %w", err)
+	}
+
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return nil, fmt.Errorf("acknowledgement query returned %s: %s", response.Status, strings.TrimSpace(string(responseBody)))
+	}
+
+	var acks struct {
+		Acks map[string]bool `json:"acks"`
+	}
+
+	if err := json.Unmarshal(responseBody, &acks); err != nil {
+		return nil, fmt.Errorf("failed to decode acknowledgement: %w", err)
+	}
+
+	acknowledged := make(map[int64]bool, len(acks.Acks))
+
+	for ackID, confirmed := range acks.Acks {
+		if id, err := strconv.ParseInt(ackID, 10, 64); err == nil {
+			acknowledged[id] = confirmed
+		}
+	}
+
+	return acknowledged, nil
+}
+
+// `Flush` sends the queued records, then waits for the acknowledgements of the sent batches,
+// sending again the batches not acknowledged in time, at most for the flush timeout.
+func (handler *SplunkHECHandler) Flush() error {
+	deadline := time.Now().Add(handler.flushTimeout)
+
+	for {
+		pending := handler.queue.flush(time.Until(deadline), handler.minBackoff)
+		unacknowledged := handler.Unacknowledged()
+
+		if pending == 0 && unacknowledged == 0 {
+			return nil
+		}
+
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			return fmt.Errorf(
+				"%w: %d records not sent and %d not acknowledged by %s", errFlushTimeout, pending, unacknowledged, handler.url,
+			)
+		}
+
+		timer := time.NewTimer(min(remaining, handler.ackPollInterval))
+
+		select {
+		case <-handler.ackUpdate:
+		case <-timer.C:
+		}
+
+		timer.Stop()
+	}
+}
+
+// `Close` waits for the queued records to be sent and acknowledged like `Flush`, stops the acknowledgement poller,
+// then stops accepting records and sends the queued ones. The records whose acknowledgement is still not confirmed
+// can no longer be sent again: they are dropped and reported to the error handler with `ErrDeliveryFailed`.
+// Closing an already closed handler does nothing.
+func (handler *SplunkHECHandler) Close() error {
+	err := handler.Flush()
+
+	// Stop the poller while the queue is still open, so that it never queues again a batch on a closed queue
+	handler.ackOnce.Do(func() { close(handler.ackDone) })
+
+	handler.ackMutex.Lock()
+	select {
+	case <-handler.ackStop:
+	default:
+		close(handler.ackStop)
+	}
+	handler.ackMutex.Unlock()
+
+	<-handler.ackDone
+
+	if closeErr := handler.HTTPHandler.Close(); closeErr != nil && err == nil {
+		err = closeErr
+	}
+
+	if dropped := handler.dropUnacknowledged(); dropped > 0 && err == nil {
+		err = fmt.Errorf("%w: %d records not acknowledged by %s", errFlushTimeout, dropped, handler.url)
+	}
+
+	return err
+}
+
+// `dropUnacknowledged` queries the pending acknowledgements a last time once the poller is stopped,
+// then drops and reports the batches still not acknowledged. It returns the number of dropped records.
+func (handler *SplunkHECHandler) dropUnacknowledged() int {
+	handler.ackMutex.Lock()
+	pending := handler.acks
+	handler.acks = make(map[int64]hecBatch)
+	handler.ackMutex.Unlock()
+
+	if len(pending) == 0 {
+		return 0
+	}
+
+	ackIDs := make([]int64, 0, len(pending))
+	for ackID := range pending {
+		ackIDs = append(ackIDs, ackID)
+	}
+
+	acknowledged, err := handler.queryAcks(ackIDs)
+	if err != nil {
+		handler.handleError(err)
+	}
+
+	dropped := 0
+
+	for ackID, batch := range pending {
+		if acknowledged[ackID] {
+			continue
+		}
+
+		dropped += len(batch.items)
+		handler.queue.dropped.Add(uint64(len(batch.items)))
+		handler.handleError(fmt.Errorf(
+			"%w: %d records not sent to %s: %w: ack id %d",
+			ErrDeliveryFailed, len(batch.items), handler.url, errNotAcknowledged, ackID,
+		))
+	}
+
+	return dropped
+}
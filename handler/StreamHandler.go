@@ -1,64 +1,72 @@
 package handler
 
 import (
-	"bufio"
+	"errors"
 	"fmt"
 	"log"
 	"os"
-	"sync"
+	"os/signal"
+	"path/filepath"
+	"time"
 
 	"github.com/ZertyCraft/GoLogger/levels"
+	"github.com/ZertyCraft/GoLogger/record"
 )
 
 // `StreamHandler` is a struct that implements the Handler interface.
+// It is a `WriterHandler` writing to the file `logDirectory/fileName`, opened on the first message.
 type StreamHandler struct {
-	BaseHandler
-	useLock        bool
+	WriterHandler
+	useFileLock    bool
 	filePermission int
 	fileName       string
 	logDirectory   string
-	bufferSize     int
-	writer         *bufio.Writer
 	file           *os.File
-	mutex          sync.Mutex
+	lockFile       *os.File
+	signalChannel  chan os.Signal
+	watchStop      chan struct{}
 }
 
 const (
-	// `defaultBufferSize` is the default buffer size for the `StreamHandler`.
-	defaultBufferSize = 4096
 	// `defaultFilePermission` is the default file permission for the `StreamHandler`.
 	defaultFilePermission = 0o644
 	// `defaultFileName` is the default file name for the `StreamHandler`.
 	defaultFileName = "log"
 	// `defaultlogDirectory` is the default log directory for the `StreamHandler`.
 	defaultlogDirectory = "logs"
-	// `defaultUseLock` is the default value for the `useLock` field of the `StreamHandler`.
-	defaultUseLock = true
+	// `defaultUseFileLock` is the default value for the `useFileLock` field of the `StreamHandler`.
+	defaultUseFileLock = false
+	// `lockFileSuffix` is appended to the file name to get the name of the inter-process lock file.
+	lockFileSuffix = ".lock"
 )
 
 // `NewStreamHandler` is a function that returns a new `StreamHandler` instance.
 // NewStreamHandler creates a new instance of StreamHandler.
 // It initializes the StreamHandler struct with default values for its fields.
+// Like the `WriterHandler`, its default formater is a `LineFormater` ("%d %l %m").
 // Returns a pointer to the newly created StreamHandler.
 func NewStreamHandler() *StreamHandler {
 	return &StreamHandler{
-		BaseHandler:    *NewBaseHandler(),
-		useLock:        defaultUseLock,
+		WriterHandler:  *NewWriterHandler(nil),
+		useFileLock:    defaultUseFileLock,
 		filePermission: defaultFilePermission,
 		fileName:       defaultFileName,
 		logDirectory:   defaultlogDirectory,
-		bufferSize:     defaultBufferSize,
 
-		writer: nil,
-		file:   nil,
-		mutex:  sync.Mutex{},
+		file:          nil,
+		lockFile:      nil,
+		signalChannel: nil,
+		watchStop:     nil,
 	}
 }
 
 // ======== Setters ========
-// `SetUseLock` sets the value of the `useLock` field of the `StreamHandler`.
-func (handler *StreamHandler) SetUseLock(useLock bool) {
-	handler.useLock = useLock
+// `SetUseFileLock` sets the value of the `useFileLock` field of the `StreamHandler`.
+// When enabled, writes are guarded by an advisory lock on `logDirectory/fileName.lock`,
+// so several processes can share the same log file. Each message is written to the file
+// immediately, and the file is reopened when another process moved it.
+func (handler *StreamHandler) SetUseFileLock(useFileLock bool) {
+	handler.useFileLock = useFileLock
 }
 
 // `SetFilePermission` sets the value of the `filePermission` field of the `StreamHandler`.
@@ -76,15 +84,10 @@ func (handler *StreamHandler) SetLogDirectory(logDirectory string) {
 	handler.logDirectory = logDirectory
 }
 
-// `SetBufferSize` sets the value of the `bufferSize` field of the `StreamHandler`.
-func (handler *StreamHandler) SetBufferSize(bufferSize int) {
-	handler.bufferSize = bufferSize
-}
-
 // ======== Getters ========
-// `GetUseLock` returns the value of the `useLock` field of the `StreamHandler`.
-func (handler *StreamHandler) GetUseLock() bool {
-	return handler.useLock
+// `GetUseFileLock` returns the value of the `useFileLock` field of the `StreamHandler`.
+func (handler *StreamHandler) GetUseFileLock() bool {
+	return handler.useFileLock
 }
 
 // `GetFilePermission` returns the value of the `filePermission` field of the `StreamHandler`.
@@ -102,11 +105,6 @@ func (handler *StreamHandler) GetLogDirectory() string {
 	return handler.logDirectory
 }
 
-// `GetBufferSize` returns the value of the `bufferSize` field of the `StreamHandler`.
-func (handler *StreamHandler) GetBufferSize() int {
-	return handler.bufferSize
-}
-
 // ======== Methods ========
 // `isOpened` checks if the file is opened.
 // isOpened checks if the StreamHandler's file is open.
@@ -142,20 +140,23 @@ func (handler *StreamHandler) open() error {
 	}
 
 	// Open the file
-	filePath := handler.logDirectory + "/" + handler.fileName
-
-	file, err := os.OpenFile(filePath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, os.FileMode(handler.filePermission))
+	file, err := os.OpenFile(handler.filePath(), os.O_CREATE|os.O_WRONLY|os.O_APPEND, os.FileMode(handler.filePermission))
 	if err != nil {
 		return fmt.Errorf("failed to open file: %w", err)
 	}
 
 	// Create a new writer
-	handler.writer = bufio.NewWriterSize(file, handler.bufferSize)
+	handler.setOutput(file)
 	handler.file = file
 
 	return nil
 }
 
+// `filePath` returns the path of the log file.
+func (handler *StreamHandler) filePath() string {
+	return filepath.Join(handler.logDirectory, handler.fileName)
+}
+
 // `close` closes the file.
 // close closes the StreamHandler by flushing the writer and closing the file.
 // If the file is not opened, it returns nil.
@@ -167,8 +168,8 @@ func (handler *StreamHandler) close() error {
 	}
 
 	// Flush the writer
-	if err := handler.writer.Flush(); err != nil {
-		return fmt.Errorf("failed to flush writer: %w", err)
+	if err := handler.flush(); err != nil {
+		return err
 	}
 
 	// Close the file
@@ -177,7 +178,7 @@ func (handler *StreamHandler) close() error {
 	}
 
 	handler.file = nil
-	handler.writer = nil
+	handler.setOutput(nil)
 
 	return nil
 }
@@ -185,89 +186,271 @@ func (handler *StreamHandler) close() error {
 // `Log` logs the given message using the handler.
 // Log writes a log message with the specified level.
 // If the file is not opened, it will attempt to open it.
-// If opening the file fails, an error will be logged and the function will return.
-// If a lock is enabled, it will acquire the lock before writing the log message.
-// If the log level is not sufficient, the function will return without writing the message.
-// The log message will be formatted using the specified formatter.
-// If formatting the message fails, an error will be logged and the function will return.
-// If the formatted message does not end with a line break, it will be added.
-// The formatted message will be written to the file.
-// If writing the message fails, an error will be logged and the function will return.
+// If opening the file fails, the error is reported to the error handler and the message is dropped.
+// If the log level is not sufficient or a filter rejects the message, it is not written.
+// The message is formatted and written to the buffer of the `WriterHandler`, which writes it to the file
+// when the buffer is full or flushed, or right away if its level is at or above the flush level.
+// With the file lock, the message is written to the file before the lock is released.
 func (handler *StreamHandler) Log(level levels.Level, message string) {
+	handler.LogRecord(record.New(level, message))
+}
+
+// `LogRecord` logs the given record using the handler, like `Log`.
+func (handler *StreamHandler) LogRecord(rec record.Record) {
+	// Acquire the lock
+	if handler.useLock {
+		handler.mutex.Lock()
+		defer handler.mutex.Unlock()
+	}
+
+	if handler.closed.Load() {
+		handler.handleError(ErrClosed)
+
+		return
+	}
+
+	// Acquire the inter-process lock
+	if handler.useFileLock {
+		if err := handler.acquireFileLock(); err != nil {
+			handler.handleError(err)
+
+			return
+		}
+		defer handler.releaseFileLock()
+
+		// Reopen the file if another process rotated it
+		if handler.isOpened() && handler.isMoved() {
+			if err := handler.reopen(); err != nil {
+				handler.handleError(err)
+
+				return
+			}
+		}
+	}
+
 	if !handler.isOpened() {
 		if err := handler.open(); err != nil {
-			log.Printf("Failed to open file: %v\n", err)
+			handler.handleError(fmt.Errorf("failed to open file: %w", err))
 
 			return
 		}
 	}
 
+	// Check if the level is sufficient and the filters allow the record
+	if !handler.shouldLog(rec) {
+		return
+	}
+
+	// Format and write the message
+	if err := handler.writeRecord(rec); err != nil {
+		handler.handleError(err)
+
+		return
+	}
+
+	// Other processes must see the message before the lock is released,
+	// and important messages must not stay in the buffer
+	if handler.useFileLock || handler.shouldFlush(rec.Level) {
+		if err := handler.flush(); err != nil {
+			handler.handleError(err)
+		}
+	}
+}
+
+// `acquireFileLock` acquires the inter-process lock, opening the lock file if needed.
+func (handler *StreamHandler) acquireFileLock() error {
+	if handler.lockFile == nil {
+		const logDirectoryPermission = 0o755
+
+		if err := os.MkdirAll(handler.logDirectory, os.FileMode(logDirectoryPermission)); err != nil {
+			return fmt.Errorf("failed to create log directory: %w", err)
+		}
+
+		file, err := os.OpenFile(
+			handler.filePath()+lockFileSuffix, os.O_CREATE|os.O_RDWR, os.FileMode(handler.filePermission),
+		)
+		if err != nil {
+			return fmt.Errorf("failed to open lock file: %w", err)
+		}
+
+		handler.lockFile = file
+	}
+
+	return lockFile(handler.lockFile)
+}
+
+// `releaseFileLock` releases the inter-process lock.
+func (handler *StreamHandler) releaseFileLock() {
+	if err := unlockFile(handler.lockFile); err != nil {
+		handler.handleError(err)
+	}
+}
+
+// `Reopen` closes the log file and opens `logDirectory/fileName` again.
+// It is meant to be called after an external tool (e.g. logrotate) moved or deleted the file,
+// so that the handler stops writing to the old file.
+// Buffered messages are flushed to the old file before it is closed.
+func (handler *StreamHandler) Reopen() error {
 	// Acquire the lock
 	if handler.useLock {
 		handler.mutex.Lock()
 		defer handler.mutex.Unlock()
 	}
 
-	// Check if the level is sufficient
-	if !handler.isLevelSufficient(level) {
-		return
+	if handler.closed.Load() {
+		return ErrClosed
 	}
 
-	// Format the message
-	formattedMessage, err := handler.formater.Format(level, message)
-	if err != nil {
-		log.Printf("Failed to format message: %v\n", err)
+	return handler.reopen()
+}
 
-		return
+// `Close` stops the background goroutines of the handler, flushes the writer and closes the file.
+// Once closed, the handler does not write messages anymore and `Flush` and `Reopen` return `ErrClosed`.
+// Closing an already closed handler does nothing.
+func (handler *StreamHandler) Close() error {
+	handler.StopReopenOnSignal()
+	handler.StopWatchFile()
+	handler.StopFlushPeriodically()
+	handler.stopSyncer()
+
+	// Acquire the lock
+	if handler.useLock {
+		handler.mutex.Lock()
+		defer handler.mutex.Unlock()
 	}
 
-	// Add line break if not present
-	if formattedMessage[len(formattedMessage)-1] != '\n' {
-		formattedMessage += "\n"
+	if handler.closed.Swap(true) {
+		return nil
 	}
 
-	// Write the message
-	if _, err := handler.writer.WriteString(formattedMessage); err != nil {
-		log.Println("Failed to write message:", err)
+	err := handler.close()
 
-		return
+	if handler.lockFile != nil {
+		if closeErr := handler.lockFile.Close(); closeErr != nil {
+			err = errors.Join(err, fmt.Errorf("failed to close lock file: %w", closeErr))
+		}
+
+		handler.lockFile = nil
 	}
+
+	return err
 }
 
-// `Flush` flushes the writer.
-// Flush flushes the writer and ensures that all buffered data is written to the underlying file.
-// If the file is not already opened, it will be opened before flushing.
-// If the StreamHandler is configured to use a lock, it will acquire the lock before flushing.
-// Returns an error if there was a problem flushing the writer or opening the file.
-func (handler *StreamHandler) Flush() error {
-	// Check if writer is nil
-	if handler.writer == nil {
-		return nil
+// `reopen` closes and opens the log file without acquiring the lock.
+func (handler *StreamHandler) reopen() error {
+	if err := handler.close(); err != nil {
+		return fmt.Errorf("failed to reopen file: %w", err)
 	}
 
-	// Check if the writer have data in buffer
-	if handler.writer.Buffered() == 0 {
-		return nil
+	if err := handler.open(); err != nil {
+		return fmt.Errorf("failed to reopen file: %w", err)
 	}
 
-	// Check if file is opened
-	if !handler.isOpened() {
-		// Open the file
-		if err := handler.open(); err != nil {
-			return fmt.Errorf("failed to open file: %w", err)
+	return nil
+}
+
+// `ReopenOnSignal` reopens the log file each time one of the given signals is received.
+// If no signal is given, SIGHUP is used (as sent by logrotate `postrotate` scripts),
+// and nothing is done on platforms without SIGHUP.
+// Calling it again replaces the previously registered signals.
+func (handler *StreamHandler) ReopenOnSignal(signals ...os.Signal) {
+	if len(signals) == 0 {
+		signals = defaultReopenSignals()
+	}
+
+	handler.StopReopenOnSignal()
+
+	if len(signals) == 0 {
+		return
+	}
+
+	signalChannel := make(chan os.Signal, 1)
+	signal.Notify(signalChannel, signals...)
+	handler.signalChannel = signalChannel
+
+	go func() {
+		for range signalChannel {
+			if err := handler.Reopen(); err != nil {
+				handler.handleError(err)
+			}
 		}
+	}()
+}
+
+// `StopReopenOnSignal` stops reopening the log file on signals.
+func (handler *StreamHandler) StopReopenOnSignal() {
+	if handler.signalChannel == nil {
+		return
+	}
+
+	signal.Stop(handler.signalChannel)
+	close(handler.signalChannel)
+	handler.signalChannel = nil
+}
+
+// `WatchFile` checks the log file every `interval` and reopens it
+// when the file at `logDirectory/fileName` was deleted or replaced by another file.
+// Calling it again restarts the watcher with the new interval.
+func (handler *StreamHandler) WatchFile(interval time.Duration) {
+	handler.StopWatchFile()
+
+	watchStop := make(chan struct{})
+	handler.watchStop = watchStop
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-watchStop:
+				return
+			case <-ticker.C:
+				if err := handler.reopenIfMoved(); err != nil {
+					handler.handleError(err)
+				}
+			}
+		}
+	}()
+}
+
+// `StopWatchFile` stops the watcher started by `WatchFile`.
+func (handler *StreamHandler) StopWatchFile() {
+	if handler.watchStop == nil {
+		return
 	}
 
+	close(handler.watchStop)
+	handler.watchStop = nil
+}
+
+// `reopenIfMoved` reopens the log file if it is opened and was moved or deleted.
+func (handler *StreamHandler) reopenIfMoved() error {
 	// Acquire the lock
 	if handler.useLock {
 		handler.mutex.Lock()
 		defer handler.mutex.Unlock()
 	}
 
-	// Flush the writer
-	if err := handler.writer.Flush(); err != nil {
-		return fmt.Errorf("failed to flush writer: %w", err)
+	if handler.closed.Load() || !handler.isOpened() || !handler.isMoved() {
+		return nil
 	}
 
-	return nil
+	return handler.reopen()
+}
+
+// `isMoved` checks if the opened file is still the one at `logDirectory/fileName`.
+// It returns true if the path no longer exists or points to another file.
+func (handler *StreamHandler) isMoved() bool {
+	pathInfo, err := os.Stat(handler.filePath())
+	if err != nil {
+		return true
+	}
+
+	fileInfo, err := handler.file.Stat()
+	if err != nil {
+		return true
+	}
+
+	return !os.SameFile(pathInfo, fileInfo)
 }
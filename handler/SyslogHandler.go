@@ -0,0 +1,32 @@
+package handler
+
+import (
+	"github.com/ZertyCraft/GoLogger/formater"
+)
+
+const (
+	// `SyslogNetwork` is the network of the local syslog socket.
+	SyslogNetwork = "unixgram"
+	// `SyslogAddress` is the address of the local syslog socket.
+	SyslogAddress = "/dev/log"
+)
+
+// `SyslogHandler` is a handler that sends records to a syslog server, through the local socket (`/dev/log`),
+// UDP or TCP. It is a `NetworkHandler` formatting records with an `RFC5424Formater` by default
+// (use an `RFC3164Formater` for servers only supporting BSD syslog), and using octet-counting framing over TCP.
+// The facility, app-name and procid are set on the formater.
+type SyslogHandler struct {
+	*NetworkHandler
+}
+
+// `NewSyslogHandler` returns a new `SyslogHandler` sending messages to `address` over `network`,
+// e.g. `SyslogNetwork` and `SyslogAddress` for the local syslog server, or "udp" and "localhost:514".
+func NewSyslogHandler(network string, address string) *SyslogHandler {
+	handler := &SyslogHandler{
+		NetworkHandler: NewNetworkHandler(network, address),
+	}
+	handler.formater = formater.NewRFC5424Formater()
+	handler.framing = FramingOctetCounting
+
+	return handler
+}
@@ -0,0 +1,475 @@
+package handler
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ZertyCraft/GoLogger/formater"
+	"github.com/ZertyCraft/GoLogger/levels"
+	"github.com/ZertyCraft/GoLogger/record"
+)
+
+// `SyncPolicy` defines when the output is synced to the disk (fsync), for outputs with a `Sync` method such as files.
+type SyncPolicy int
+
+const (
+	// `SyncNever` leaves syncing to the operating system.
+	SyncNever SyncPolicy = iota
+	// `SyncOnFlush` syncs the output each time the writer is flushed.
+	SyncOnFlush
+	// `SyncPeriodic` syncs the output every sync interval in a background goroutine,
+	// and on flush at most once per sync interval.
+	SyncPeriodic
+)
+
+// `syncer` is an output that can be synced to the disk, e.g. an `*os.File`.
+type syncer interface {
+	Sync() error
+}
+
+// `errNoOutput` is reported when a `WriterHandler` has no writer to write to.
+var errNoOutput = errors.New("no output writer")
+
+// `WriterHandler` is a handler that writes formatted records, one per line, to any `io.Writer`
+// (pipes, sockets, `bytes.Buffer`, compressed writers...).
+// The messages are buffered until the buffer is full or the handler is flushed,
+// and the writes are guarded by a lock so that the handler can be shared between goroutines.
+// The `StreamHandler` is a `WriterHandler` writing to a log file.
+type WriterHandler struct {
+	BaseHandler
+	useLock       bool
+	bufferSize    int
+	flushLevel    levels.Level
+	useFlushLevel bool
+	syncPolicy    SyncPolicy
+	syncInterval  time.Duration
+	lastSync      time.Time
+	dirty         bool // Whether data was written since the last sync
+	closeOutput   bool // Whether `Close` closes the output
+	output        io.Writer
+	writer        *bufio.Writer // Created on the first message, buffering the output
+	mutex         sync.Mutex
+	closed        atomic.Bool
+	flushStop     chan struct{}
+	syncStop      chan struct{} // Stops the background syncer of `SyncPeriodic`
+}
+
+const (
+	// `defaultBufferSize` is the default buffer size for the `WriterHandler`.
+	defaultBufferSize = 4096
+	// `defaultUseLock` is the default value for the `useLock` field of the `WriterHandler`.
+	defaultUseLock = true
+	// `defaultSyncPolicy` is the default value for the `syncPolicy` field of the `WriterHandler`.
+	defaultSyncPolicy = SyncNever
+	// `defaultSyncInterval` is the default value for the `syncInterval` field of the `WriterHandler`.
+	defaultSyncInterval = time.Second
+)
+
+// `NewWriterHandler` returns a new `WriterHandler` writing to `output`.
+// The output is not closed when the handler is closed, unless `SetCloseOutput` is enabled.
+func NewWriterHandler(output io.Writer) *WriterHandler {
+	handler := &WriterHandler{
+		BaseHandler:   *NewBaseHandler(),
+		useLock:       defaultUseLock,
+		bufferSize:    defaultBufferSize,
+		flushLevel:    levels.CRITICAL,
+		useFlushLevel: false,
+		syncPolicy:    defaultSyncPolicy,
+		syncInterval:  defaultSyncInterval,
+		lastSync:      time.Time{},
+		dirty:         false,
+		closeOutput:   false,
+		output:        output,
+		writer:        nil,
+		mutex:         sync.Mutex{},
+		closed:        atomic.Bool{},
+		flushStop:     nil,
+		syncStop:      nil,
+	}
+	handler.formater = formater.NewLineFormater()
+
+	return handler
+}
+
+// ======== Setters ========
+// `SetUseLock` sets the value of the `useLock` field of the `WriterHandler`.
+func (handler *WriterHandler) SetUseLock(useLock bool) {
+	handler.useLock = useLock
+}
+
+// `SetBufferSize` sets the value of the `bufferSize` field of the `WriterHandler`.
+// If messages were already logged, the buffered messages are written to the output
+// before the buffer is replaced by one of the new size.
+func (handler *WriterHandler) SetBufferSize(bufferSize int) {
+	// Acquire the lock
+	if handler.useLock {
+		handler.mutex.Lock()
+		defer handler.mutex.Unlock()
+	}
+
+	handler.bufferSize = bufferSize
+
+	if handler.writer == nil {
+		return
+	}
+
+	if err := handler.writer.Flush(); err != nil {
+		handler.handleError(fmt.Errorf("failed to flush writer: %w", err))
+
+		return
+	}
+
+	handler.writer = bufio.NewWriterSize(handler.output, bufferSize)
+}
+
+// `SetFlushLevel` makes the handler flush the writer right after writing a message
+// at or above the given level (e.g. ERROR), so that it is not lost on crash.
+func (handler *WriterHandler) SetFlushLevel(flushLevel levels.Level) {
+	handler.flushLevel = flushLevel
+	handler.useFlushLevel = true
+}
+
+// `DisableFlushLevel` disables the flush after messages set with `SetFlushLevel`.
+func (handler *WriterHandler) DisableFlushLevel() {
+	handler.useFlushLevel = false
+}
+
+// `SetSyncPolicy` sets the value of the `syncPolicy` field of the `WriterHandler`.
+// It only applies to outputs with a `Sync` method, such as files.
+// With `SyncPeriodic`, a background goroutine flushes and syncs the output every sync interval until `Close`.
+func (handler *WriterHandler) SetSyncPolicy(syncPolicy SyncPolicy) {
+	handler.syncPolicy = syncPolicy
+	handler.restartSyncer()
+}
+
+// `SetSyncInterval` sets the value of the `syncInterval` field of the `WriterHandler` (used by `SyncPeriodic`).
+// Intervals that are not positive are set to `defaultSyncInterval`.
+func (handler *WriterHandler) SetSyncInterval(syncInterval time.Duration) {
+	if syncInterval <= 0 {
+		syncInterval = defaultSyncInterval
+	}
+
+	handler.syncInterval = syncInterval
+	handler.restartSyncer()
+}
+
+// `SetCloseOutput` sets whether `Close` closes the output when it is an `io.Closer`,
+// e.g. to write the footer of a `gzip.Writer`.
+func (handler *WriterHandler) SetCloseOutput(closeOutput bool) {
+	handler.closeOutput = closeOutput
+}
+
+// ======== Getters ========
+// `GetUseLock` returns the value of the `useLock` field of the `WriterHandler`.
+func (handler *WriterHandler) GetUseLock() bool {
+	return handler.useLock
+}
+
+// `GetBufferSize` returns the value of the `bufferSize` field of the `WriterHandler`.
+func (handler *WriterHandler) GetBufferSize() int {
+	return handler.bufferSize
+}
+
+// `GetFlushLevel` returns the flush level and whether it is enabled.
+func (handler *WriterHandler) GetFlushLevel() (levels.Level, bool) {
+	return handler.flushLevel, handler.useFlushLevel
+}
+
+// `GetSyncPolicy` returns the value of the `syncPolicy` field of the `WriterHandler`.
+func (handler *WriterHandler) GetSyncPolicy() SyncPolicy {
+	return handler.syncPolicy
+}
+
+// `GetSyncInterval` returns the value of the `syncInterval` field of the `WriterHandler`.
+func (handler *WriterHandler) GetSyncInterval() time.Duration {
+	return handler.syncInterval
+}
+
+// `GetCloseOutput` returns the value of the `closeOutput` field of the `WriterHandler`.
+func (handler *WriterHandler) GetCloseOutput() bool {
+	return handler.closeOutput
+}
+
+// `GetOutput` returns the writer the handler writes to.
+func (handler *WriterHandler) GetOutput() io.Writer {
+	return handler.output
+}
+
+// ======== Methods ========
+// `setOutput` replaces the output without acquiring the lock, with a new buffer.
+// The buffer of the previous output must have been flushed.
+func (handler *WriterHandler) setOutput(output io.Writer) {
+	handler.output = output
+	handler.writer = nil
+	handler.dirty = false
+
+	if output != nil {
+		handler.writer = bufio.NewWriterSize(output, handler.bufferSize)
+	}
+}
+
+// `Log` logs the given message using the handler.
+func (handler *WriterHandler) Log(level levels.Level, message string) {
+	handler.LogRecord(record.New(level, message))
+}
+
+// `LogRecord` logs the given record using the handler, like `Log`.
+func (handler *WriterHandler) LogRecord(rec record.Record) {
+	// Acquire the lock
+	if handler.useLock {
+		handler.mutex.Lock()
+		defer handler.mutex.Unlock()
+	}
+
+	if handler.closed.Load() {
+		handler.handleError(ErrClosed)
+
+		return
+	}
+
+	// Check if the level is sufficient and the filters allow the record
+	if !handler.shouldLog(rec) {
+		return
+	}
+
+	if err := handler.writeRecord(rec); err != nil {
+		handler.handleError(err)
+
+		return
+	}
+
+	if handler.shouldFlush(rec.Level) {
+		if err := handler.flush(); err != nil {
+			handler.handleError(err)
+		}
+	}
+}
+
+// `writeRecord` formats the record and writes it to the buffer without acquiring the lock,
+// adding a line break if not present.
+func (handler *WriterHandler) writeRecord(rec record.Record) error {
+	if handler.writer == nil {
+		if handler.output == nil {
+			return errNoOutput
+		}
+
+		handler.writer = bufio.NewWriterSize(handler.output, handler.bufferSize)
+	}
+
+	// Format the message
+	formattedMessage, err := formater.FormatRecord(handler.formater, rec)
+	if err != nil {
+		return fmt.Errorf("failed to format message: %w", err)
+	}
+
+	// Add line break if not present
+	if formattedMessage == "" || formattedMessage[len(formattedMessage)-1] != '\n' {
+		formattedMessage += "\n"
+	}
+
+	// Write the message
+	if _, err := handler.writer.WriteString(formattedMessage); err != nil {
+		return fmt.Errorf("failed to write message: %w", err)
+	}
+
+	handler.dirty = true
+
+	return nil
+}
+
+// `shouldFlush` checks if a message of the level must be flushed right after being written.
+func (handler *WriterHandler) shouldFlush(level levels.Level) bool {
+	return handler.useFlushLevel && level >= handler.flushLevel
+}
+
+// `flush` flushes the writer without acquiring the lock, then syncs the output according to the sync policy.
+func (handler *WriterHandler) flush() error {
+	if handler.writer == nil {
+		return nil
+	}
+
+	if err := handler.writer.Flush(); err != nil {
+		return fmt.Errorf("failed to flush writer: %w", err)
+	}
+
+	return handler.sync()
+}
+
+// `sync` syncs the output to the disk if data was written since the last sync and the sync policy requires it.
+func (handler *WriterHandler) sync() error {
+	output, ok := handler.output.(syncer)
+	if !ok || !handler.dirty {
+		return nil
+	}
+
+	switch handler.syncPolicy {
+	case SyncNever:
+		return nil
+	case SyncOnFlush:
+	case SyncPeriodic:
+		if time.Since(handler.lastSync) < handler.syncInterval {
+			return nil
+		}
+	}
+
+	return handler.syncOutput(output)
+}
+
+// `syncOutput` syncs the output to the disk without acquiring the lock.
+func (handler *WriterHandler) syncOutput(output syncer) error {
+	if err := output.Sync(); err != nil {
+		return fmt.Errorf("failed to sync file: %w", err)
+	}
+
+	handler.lastSync = time.Now()
+	handler.dirty = false
+
+	return nil
+}
+
+// `restartSyncer` stops the background syncer, then starts a new one if the sync policy is `SyncPeriodic`.
+func (handler *WriterHandler) restartSyncer() {
+	handler.stopSyncer()
+
+	if handler.syncPolicy != SyncPeriodic || handler.closed.Load() {
+		return
+	}
+
+	syncStop := make(chan struct{})
+	handler.syncStop = syncStop
+	interval := handler.syncInterval
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-syncStop:
+				return
+			case <-ticker.C:
+				if err := handler.syncPeriodically(); err != nil {
+					handler.handleError(err)
+				}
+			}
+		}
+	}()
+}
+
+// `stopSyncer` stops the background syncer started for `SyncPeriodic`.
+func (handler *WriterHandler) stopSyncer() {
+	if handler.syncStop == nil {
+		return
+	}
+
+	close(handler.syncStop)
+	handler.syncStop = nil
+}
+
+// `syncPeriodically` flushes the writer and syncs the output if data was written since the last sync,
+// so that messages logged in a burst followed by silence still reach the disk.
+func (handler *WriterHandler) syncPeriodically() error {
+	// Acquire the lock
+	if handler.useLock {
+		handler.mutex.Lock()
+		defer handler.mutex.Unlock()
+	}
+
+	output, ok := handler.output.(syncer)
+	if !ok || handler.closed.Load() || handler.writer == nil || !handler.dirty {
+		return nil
+	}
+
+	if err := handler.writer.Flush(); err != nil {
+		return fmt.Errorf("failed to flush writer: %w", err)
+	}
+
+	return handler.syncOutput(output)
+}
+
+// `Flush` flushes the writer, so that all buffered messages are written to the output,
+// then syncs the output according to the sync policy.
+// If the `WriterHandler` is configured to use a lock, it will acquire the lock before flushing.
+func (handler *WriterHandler) Flush() error {
+	// Acquire the lock
+	if handler.useLock {
+		handler.mutex.Lock()
+		defer handler.mutex.Unlock()
+	}
+
+	if handler.closed.Load() {
+		return ErrClosed
+	}
+
+	return handler.flush()
+}
+
+// `FlushPeriodically` flushes the writer every `interval` in a background goroutine,
+// so that messages do not stay in the buffer when few messages are logged.
+// Calling it again restarts the flusher with the new interval.
+func (handler *WriterHandler) FlushPeriodically(interval time.Duration) {
+	handler.StopFlushPeriodically()
+
+	flushStop := make(chan struct{})
+	handler.flushStop = flushStop
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-flushStop:
+				return
+			case <-ticker.C:
+				if err := handler.Flush(); err != nil {
+					handler.handleError(err)
+				}
+			}
+		}
+	}()
+}
+
+// `StopFlushPeriodically` stops the flusher started by `FlushPeriodically`.
+func (handler *WriterHandler) StopFlushPeriodically() {
+	if handler.flushStop == nil {
+		return
+	}
+
+	close(handler.flushStop)
+	handler.flushStop = nil
+}
+
+// `Close` stops the background flusher and syncer and flushes the writer,
+// then closes the output if `SetCloseOutput` is enabled.
+// Once closed, the handler does not write messages anymore and `Flush` returns `ErrClosed`.
+// Closing an already closed handler does nothing.
+func (handler *WriterHandler) Close() error {
+	handler.StopFlushPeriodically()
+	handler.stopSyncer()
+
+	// Acquire the lock
+	if handler.useLock {
+		handler.mutex.Lock()
+		defer handler.mutex.Unlock()
+	}
+
+	if handler.closed.Swap(true) {
+		return nil
+	}
+
+	err := handler.flush()
+
+	if closer, ok := handler.output.(io.Closer); ok && handler.closeOutput {
+		if closeErr := closer.Close(); closeErr != nil {
+			err = errors.Join(err, fmt.Errorf("failed to close output: %w", closeErr))
+		}
+	}
+
+	return err
+}
@@ -0,0 +1,48 @@
+package handlertest
+
+import (
+	"github.com/ZertyCraft/GoLogger/handler"
+	"github.com/ZertyCraft/GoLogger/levels"
+	"github.com/ZertyCraft/GoLogger/record"
+)
+
+// `levelFilter` holds the level and the filters of a test handler, like `handler.BaseHandler`.
+type levelFilter struct {
+	level   levels.Level
+	filters []handler.Filter
+}
+
+// `SetLevel` sets the minimum level of the records handled.
+func (f *levelFilter) SetLevel(level levels.Level) {
+	f.level = level
+}
+
+// `GetLevel` returns the minimum level of the records handled.
+func (f *levelFilter) GetLevel() levels.Level {
+	return f.level
+}
+
+// `AddFilter` adds a filter. A record is handled only if all filters allow it.
+func (f *levelFilter) AddFilter(filter handler.Filter) {
+	f.filters = append(f.filters, filter)
+}
+
+// `GetFilters` returns the filters.
+func (f *levelFilter) GetFilters() []handler.Filter {
+	return f.filters
+}
+
+// `allows` checks if the record has a sufficient level and is allowed by the filters.
+func (f *levelFilter) allows(rec record.Record) bool {
+	if rec.Level < f.level {
+		return false
+	}
+
+	for _, filter := range f.filters {
+		if !filter.Allow(rec) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,99 @@
+// Package handlertest provides handlers to check and show log output in tests.
+package handlertest
+
+import (
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/ZertyCraft/GoLogger/levels"
+	"github.com/ZertyCraft/GoLogger/record"
+)
+
+// `Recorder` is a handler that stores the records it receives, so tests can assert on them.
+type Recorder struct {
+	levelFilter
+	records []record.Record
+	mutex   sync.Mutex
+}
+
+// `NewRecorder` returns a new `Recorder` recording records of all levels.
+func NewRecorder() *Recorder {
+	return &Recorder{
+		levelFilter: levelFilter{level: levels.DEBUG, filters: nil},
+		records:     make([]record.Record, 0),
+		mutex:       sync.Mutex{},
+	}
+}
+
+// `Log` records the message.
+func (r *Recorder) Log(level levels.Level, message string) {
+	r.LogRecord(record.New(level, message))
+}
+
+// `LogRecord` records the record if its level is sufficient and the filters allow it.
+func (r *Recorder) LogRecord(rec record.Record) {
+	if !r.allows(rec) {
+		return
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.records = append(r.records, rec)
+}
+
+// `Records` returns a copy of the recorded records, oldest first.
+func (r *Recorder) Records() []record.Record {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	return append(make([]record.Record, 0, len(r.records)), r.records...)
+}
+
+// `Reset` removes the recorded records.
+func (r *Recorder) Reset() {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.records = r.records[:0]
+}
+
+// `Find` returns the first record with the given level and a message containing `substring`.
+func (r *Recorder) Find(level levels.Level, substring string) (record.Record, bool) {
+	for _, rec := range r.Records() {
+		if rec.Level == level && strings.Contains(rec.Message, substring) {
+			return rec, true
+		}
+	}
+
+	return record.Record{}, false
+}
+
+// `AssertLogged` reports an error if no record has the given level and a message containing `substring`.
+func (r *Recorder) AssertLogged(t testing.TB, level levels.Level, substring string) {
+	t.Helper()
+
+	if _, ok := r.Find(level, substring); !ok {
+		t.Errorf("no %v record containing %q was logged, got:\n%s", level, substring, r.dump())
+	}
+}
+
+// `AssertNotLogged` reports an error if a record has the given level and a message containing `substring`.
+func (r *Recorder) AssertNotLogged(t testing.TB, level levels.Level, substring string) {
+	t.Helper()
+
+	if rec, ok := r.Find(level, substring); ok {
+		t.Errorf("unexpected %v record containing %q was logged: %q", level, substring, rec.Message)
+	}
+}
+
+// `dump` returns the recorded records, one per line, for error messages.
+func (r *Recorder) dump() string {
+	lines := make([]string, 0)
+	for _, rec := range r.Records() {
+		lines = append(lines, "\t"+rec.Level.String()+" "+rec.Message)
+	}
+
+	return strings.Join(lines, "\n")
+}
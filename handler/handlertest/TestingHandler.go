@@ -0,0 +1,83 @@
+package handlertest
+
+import (
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/ZertyCraft/GoLogger/formater"
+	"github.com/ZertyCraft/GoLogger/levels"
+	"github.com/ZertyCraft/GoLogger/record"
+)
+
+// `TestingHandler` is a handler that writes records with `t.Log`,
+// so they are shown with the output of the test that logged them.
+// Records logged after the test completed are dropped.
+type TestingHandler struct {
+	levelFilter
+	formater  formater.Formater
+	t         testing.TB
+	completed bool
+	mutex     sync.Mutex
+}
+
+// `NewTestingHandler` returns a new `TestingHandler` for the given test,
+// logging records of all levels with the format "%l %m".
+func NewTestingHandler(t testing.TB) *TestingHandler {
+	lineFormater := formater.NewLineFormater()
+	lineFormater.SetFormat("%l %m")
+
+	testingHandler := &TestingHandler{
+		levelFilter: levelFilter{level: levels.DEBUG, filters: nil},
+		formater:    lineFormater,
+		t:           t,
+		completed:   false,
+		mutex:       sync.Mutex{},
+	}
+
+	t.Cleanup(func() {
+		testingHandler.mutex.Lock()
+		defer testingHandler.mutex.Unlock()
+
+		testingHandler.completed = true
+	})
+
+	return testingHandler
+}
+
+// `SetFormater` sets the formater of the lines written with `t.Log`.
+func (h *TestingHandler) SetFormater(formater formater.Formater) {
+	h.formater = formater
+}
+
+// `Log` writes the message with `t.Log`.
+func (h *TestingHandler) Log(level levels.Level, message string) {
+	h.t.Helper()
+
+	h.LogRecord(record.New(level, message))
+}
+
+// `LogRecord` writes the record with `t.Log` if its level is sufficient and the filters allow it.
+func (h *TestingHandler) LogRecord(rec record.Record) {
+	h.t.Helper()
+
+	if !h.allows(rec) {
+		return
+	}
+
+	line, err := formater.FormatRecord(h.formater, rec)
+	if err != nil {
+		h.t.Errorf("failed to format message: %v", err)
+
+		return
+	}
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	if h.completed {
+		return
+	}
+
+	h.t.Log(strings.TrimSuffix(line, "\n"))
+}
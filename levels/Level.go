@@ -1,5 +1,10 @@
 package levels
 
+import (
+	"fmt"
+	"strings"
+)
+
 type Level int
 
 const (
@@ -27,3 +32,22 @@ func (l Level) String() string {
 
 	return "UNKNOWN"
 }
+
+// `Parse` is a function that returns the log level of the given name (case insensitive).
+// "WARNING" is accepted as an alias of "WARN".
+func Parse(name string) (Level, error) {
+	switch strings.ToUpper(name) {
+	case "DEBUG":
+		return DEBUG, nil
+	case "INFO":
+		return INFO, nil
+	case "WARN", "WARNING":
+		return WARN, nil
+	case "ERROR":
+		return ERROR, nil
+	case "CRITICAL":
+		return CRITICAL, nil
+	}
+
+	return DEBUG, fmt.Errorf("unknown log level: %q", name)
+}
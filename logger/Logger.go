@@ -1,22 +1,69 @@
 package logger
 
 import (
+	"errors"
+	"runtime"
+	"sync/atomic"
+
 	"github.com/ZertyCraft/GoLogger/handler"
 	"github.com/ZertyCraft/GoLogger/levels"
+	"github.com/ZertyCraft/GoLogger/record"
 )
 
 // `Logger` is a struct that contains a slice of handlers.
 type Logger struct {
 	handler []handler.Handler
+	name    string
+	fields  []record.Field
+	caller  bool // Whether the location of the calls is added to the records
+	closed  atomic.Bool
 }
 
 // `NewLogger` is a function that returns a new instance of Logger.
 func NewLogger() *Logger {
 	return &Logger{
 		handler: make([]handler.Handler, 0),
+		name:    "",
+		fields:  nil,
+		caller:  false,
+		closed:  atomic.Bool{},
 	}
 }
 
+// `SetName` is a method that sets the name of the logger, added to every record.
+func (l *Logger) SetName(name string) {
+	l.name = name
+}
+
+// `GetName` is a method that returns the name of the logger.
+func (l *Logger) GetName() string {
+	return l.name
+}
+
+// `SetReportCaller` is a method that sets whether the file, line and function the messages are logged from
+// are added to the records. Capturing them has a cost, so it is disabled by default.
+func (l *Logger) SetReportCaller(reportCaller bool) {
+	l.caller = reportCaller
+}
+
+// `GetReportCaller` is a method that returns whether the location of the calls is added to the records.
+func (l *Logger) GetReportCaller() bool {
+	return l.caller
+}
+
+// `With` is a method that returns a new logger with the same name and handlers,
+// adding the given fields to every record.
+// Handlers added to the new logger are not added to the original one, and the other way around.
+func (l *Logger) With(fields ...record.Field) *Logger {
+	child := NewLogger()
+	child.handler = append(child.handler, l.handler...)
+	child.name = l.name
+	child.caller = l.caller
+	child.fields = append(append(make([]record.Field, 0, len(l.fields)+len(fields)), l.fields...), fields...)
+
+	return child
+}
+
 // `AddHandler` is a method that adds a handler to the logger.
 func (l *Logger) AddHandler(handler handler.Handler) {
 	l.handler = append(l.handler, handler)
@@ -32,33 +79,110 @@ func (l *Logger) RemoveHandler(handler handler.Handler) {
 }
 
 // `Log` is a method that logs a message with the provided log level.
-func (l *Logger) Log(level levels.Level, message string) {
+// Messages logged after `Close` are dropped.
+func (l *Logger) Log(level levels.Level, message string, fields ...record.Field) {
+	l.log(level, message, fields)
+}
+
+// `log` is a method that logs a message for `Log` and the level methods,
+// which must call it directly so that the caller is found at the same depth.
+func (l *Logger) log(level levels.Level, message string, fields []record.Field) {
+	if l.closed.Load() {
+		return
+	}
+
+	rec := record.New(level, message)
+	rec.LoggerName = l.name
+
+	if len(l.fields) > 0 || len(fields) > 0 {
+		rec.Fields = append(append(make([]record.Field, 0, len(l.fields)+len(fields)), l.fields...), fields...)
+	}
+
+	if l.caller {
+		rec.Caller = caller()
+	}
+
 	for _, h := range l.handler {
-		h.Log(level, message)
+		handler.LogRecord(h, rec)
+	}
+}
+
+// `callerDepth` is the number of frames between `caller` and the code calling the logger.
+const callerDepth = 3
+
+// `caller` is a function that returns the location of the code calling the logger.
+func caller() record.Caller {
+	pc, file, line, ok := runtime.Caller(callerDepth)
+	if !ok {
+		return record.Caller{File: "", Line: 0, Function: ""}
 	}
+
+	function := ""
+	if fn := runtime.FuncForPC(pc); fn != nil {
+		function = fn.Name()
+	}
+
+	return record.Caller{File: file, Line: line, Function: function}
 }
 
 // `Debug` is a method that logs a message with the DEBUG log level.
-func (l *Logger) Debug(message string) {
-	l.Log(levels.DEBUG, message)
+func (l *Logger) Debug(message string, fields ...record.Field) {
+	l.log(levels.DEBUG, message, fields)
 }
 
 // `Info` is a method that logs a message with the INFO log level.
-func (l *Logger) Info(message string) {
-	l.Log(levels.INFO, message)
+func (l *Logger) Info(message string, fields ...record.Field) {
+	l.log(levels.INFO, message, fields)
 }
 
 // `Warning` is a method that logs a message with the WARN log level.
-func (l *Logger) Warning(message string) {
-	l.Log(levels.WARN, message)
+func (l *Logger) Warning(message string, fields ...record.Field) {
+	l.log(levels.WARN, message, fields)
 }
 
 // `Error` is a method that logs a message with the ERROR log level.
-func (l *Logger) Error(message string) {
-	l.Log(levels.ERROR, message)
+func (l *Logger) Error(message string, fields ...record.Field) {
+	l.log(levels.ERROR, message, fields)
 }
 
 // `Critical` is a method that logs a message with the CRITICAL log level.
-func (l *Logger) Critical(message string) {
-	l.Log(levels.CRITICAL, message)
+func (l *Logger) Critical(message string, fields ...record.Field) {
+	l.log(levels.CRITICAL, message, fields)
+}
+
+// `Sync` is a method that flushes every handler implementing `handler.Flusher`.
+// It returns the errors of all handlers joined together.
+func (l *Logger) Sync() error {
+	errs := make([]error, 0)
+
+	for _, h := range l.handler {
+		if flusher, ok := h.(handler.Flusher); ok {
+			errs = append(errs, flusher.Flush())
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// `Close` is a method that closes every handler implementing `handler.Closer`
+// and flushes the other ones implementing `handler.Flusher`.
+// It returns the errors of all handlers joined together.
+// Once closed, the logger drops messages. Closing it again does nothing.
+func (l *Logger) Close() error {
+	if l.closed.Swap(true) {
+		return nil
+	}
+
+	errs := make([]error, 0)
+
+	for _, h := range l.handler {
+		switch h := h.(type) {
+		case handler.Closer:
+			errs = append(errs, h.Close())
+		case handler.Flusher:
+			errs = append(errs, h.Flush())
+		}
+	}
+
+	return errors.Join(errs...)
 }
@@ -0,0 +1,58 @@
+package record
+
+import (
+	"time"
+
+	"github.com/ZertyCraft/GoLogger/levels"
+)
+
+// `Field` is a key/value pair attached to a record (e.g. a request id).
+type Field struct {
+	Key   string
+	Value any
+}
+
+// `Caller` is the location in the source code a record was logged from.
+type Caller struct {
+	File     string // The path of the source file
+	Line     int    // The line in the source file
+	Function string // The fully qualified name of the function
+}
+
+// `Record` is a log message with its context.
+type Record struct {
+	Time       time.Time    // The time the message was logged
+	Level      levels.Level // The level of the message
+	Message    string       // The message
+	LoggerName string       // The name of the logger, empty if not set
+	Fields     []Field      // The fields of the logger and of the message
+	Caller     Caller       // The location the message was logged from, zero if not captured
+}
+
+// `New` returns a new record for the given level and message, logged now.
+func New(level levels.Level, message string) Record {
+	return Record{
+		Time:       time.Now(),
+		Level:      level,
+		Message:    message,
+		LoggerName: "",
+		Fields:     nil,
+		Caller:     Caller{File: "", Line: 0, Function: ""},
+	}
+}
+
+// `Field` returns the value of the last field with the given key, and whether it was found.
+func (r Record) Field(key string) (any, bool) {
+	for i := len(r.Fields) - 1; i >= 0; i-- {
+		if r.Fields[i].Key == key {
+			return r.Fields[i].Value, true
+		}
+	}
+
+	return nil, false
+}
+
+// `HasCaller` checks if the location the record was logged from was captured.
+func (r Record) HasCaller() bool {
+	return r.Caller.File != ""
+}
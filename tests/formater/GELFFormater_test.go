@@ -0,0 +1,71 @@
+package formater_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ZertyCraft/GoLogger/formater"
+	"github.com/ZertyCraft/GoLogger/levels"
+	"github.com/ZertyCraft/GoLogger/record"
+)
+
+// `TestGELFFormater_FormatRecord` tests the GELF messages of records.
+func TestGELFFormater_FormatRecord(t *testing.T) {
+	t.Parallel()
+
+	gelfFormater := formater.NewGELFFormater()
+	gelfFormater.SetHost("web-1")
+
+	rec := record.New(levels.ERROR, "request failed\nstack trace")
+	rec.Time = time.Unix(1700000000, 250000000)
+	rec.LoggerName = "http"
+	rec.Fields = []record.Field{{Key: "status", Value: 500}, {Key: "id", Value: "abc"}, {Key: "user name", Value: "bob"}}
+
+	got, err := gelfFormater.FormatRecord(rec)
+	if err != nil {
+		t.Fatalf("FormatRecord() error = %v", err)
+	}
+
+	var decoded map[string]any
+	if err := json.Unmarshal([]byte(got), &decoded); err != nil {
+		t.Fatalf("FormatRecord() = %s, not JSON: %v", got, err)
+	}
+
+	want := map[string]any{
+		"version":       "1.1",
+		"host":          "web-1",
+		"timestamp":     1700000000.25,
+		"level":         float64(3),
+		"short_message": "request failed",
+		"full_message":  "request failed\nstack trace",
+		"_logger":       "http",
+		"_status":       float64(500),
+		"__id":          "abc",
+		"_user_name":    "bob",
+	}
+
+	if !reflect.DeepEqual(decoded, want) {
+		t.Errorf("FormatRecord() = %v, want %v", decoded, want)
+	}
+}
+
+// `TestGELFFormater_SingleLine` tests that single-line messages have no full message.
+func TestGELFFormater_SingleLine(t *testing.T) {
+	t.Parallel()
+
+	got, err := formater.NewGELFFormater().Format(levels.DEBUG, "ready")
+	if err != nil {
+		t.Fatalf("Format() error = %v", err)
+	}
+
+	var decoded map[string]any
+	if err := json.Unmarshal([]byte(got), &decoded); err != nil {
+		t.Fatalf("Format() = %s, not JSON: %v", got, err)
+	}
+
+	if _, ok := decoded["full_message"]; ok || decoded["short_message"] != "ready" || decoded["level"] != float64(7) {
+		t.Errorf("Format() = %v, want a DEBUG short message only", decoded)
+	}
+}
@@ -0,0 +1,64 @@
+package formater_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ZertyCraft/GoLogger/formater"
+	"github.com/ZertyCraft/GoLogger/levels"
+	"github.com/ZertyCraft/GoLogger/record"
+)
+
+// `TestJSONFormater_FormatRecord` tests the JSON representation of records.
+func TestJSONFormater_FormatRecord(t *testing.T) {
+	t.Parallel()
+
+	rec := record.New(levels.ERROR, "failed")
+	rec.Time = time.Date(2024, time.March, 5, 14, 3, 9, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		modify func(rec *record.Record)
+		want   string
+	}{
+		{
+			name:   "Minimal",
+			modify: func(_ *record.Record) {},
+			want:   `{"time":"2024-03-05T14:03:09Z","level":"ERROR","message":"failed"}`,
+		},
+		{
+			name: "Full",
+			modify: func(rec *record.Record) {
+				rec.LoggerName = "db"
+				rec.Caller = record.Caller{File: "main.go", Line: 12, Function: "main.main"}
+				rec.Fields = []record.Field{
+					{Key: "err", Value: errors.New("timeout")},
+					{Key: "retries", Value: 3},
+					{Key: "complex", Value: complex(1, 2)},
+				}
+			},
+			want: `{"time":"2024-03-05T14:03:09Z","level":"ERROR","message":"failed","logger":"db",` +
+				`"caller":"main.go:12","fields":{"complex":"(1+2i)","err":"timeout","retries":3}}`,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			rec := rec
+			test.modify(&rec)
+
+			got, err := formater.NewJSONFormater().FormatRecord(rec)
+			if err != nil {
+				t.Fatalf("FormatRecord() error = %v", err)
+			}
+
+			if got != test.want {
+				t.Errorf("FormatRecord() = %s, want %s", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package formater_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ZertyCraft/GoLogger/formater"
+	"github.com/ZertyCraft/GoLogger/levels"
+	"github.com/ZertyCraft/GoLogger/record"
+)
+
+// `newTestSyslogRecord` returns a record with a fixed time and the given fields.
+func newTestSyslogRecord(level levels.Level, message string, fields ...record.Field) record.Record {
+	rec := record.New(level, message)
+	rec.Time = time.Date(2024, time.March, 5, 14, 3, 9, 120000000, time.UTC)
+	rec.Fields = fields
+
+	return rec
+}
+
+// `TestSyslogSeverity` tests the mapping of the levels to syslog severities.
+func TestSyslogSeverity(t *testing.T) {
+	t.Parallel()
+
+	tests := map[levels.Level]int{
+		levels.DEBUG:    7,
+		levels.INFO:     6,
+		levels.WARN:     4,
+		levels.ERROR:    3,
+		levels.CRITICAL: 2,
+	}
+
+	for level, want := range tests {
+		if got := formater.SyslogSeverity(level); got != want {
+			t.Errorf("SyslogSeverity(%v) = %d, want %d", level, got, want)
+		}
+	}
+}
+
+// `TestRFC5424Formater_FormatRecord` tests the header and the structured data of RFC 5424 messages.
+func TestRFC5424Formater_FormatRecord(t *testing.T) {
+	t.Parallel()
+
+	rfc5424Formater := formater.NewRFC5424Formater()
+	rfc5424Formater.SetFacility(formater.FacilityLocal0)
+	rfc5424Formater.SetHostname("host")
+	rfc5424Formater.SetAppName("app")
+	rfc5424Formater.SetProcID("42")
+
+	tests := []struct {
+		name string
+		rec  record.Record
+		want string
+	}{
+		{
+			name: "WithoutFields",
+			rec:  newTestSyslogRecord(levels.ERROR, "disk full"),
+			want: "<131>1 2024-03-05T14:03:09.120000Z host app 42 - - disk full",
+		},
+		{
+			name: "WithFields",
+			rec: newTestSyslogRecord(levels.INFO, "request",
+				record.Field{Key: "path", Value: `/a"b]\c`},
+				record.Field{Key: "bad key=", Value: 200},
+			),
+			want: `<134>1 2024-03-05T14:03:09.120000Z host app 42 - [fields@32473 path="/a\"b\]\\c" bad_key_="200"] request`,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := rfc5424Formater.FormatRecord(test.rec)
+			if err != nil {
+				t.Fatalf("FormatRecord() error = %v", err)
+			}
+
+			if got != test.want {
+				t.Errorf("FormatRecord() = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+// `TestRFC5424Formater_EmptyHeader` tests that empty header values are written as "-".
+func TestRFC5424Formater_EmptyHeader(t *testing.T) {
+	t.Parallel()
+
+	rfc5424Formater := formater.NewRFC5424Formater()
+	rfc5424Formater.SetHostname("")
+	rfc5424Formater.SetAppName("my app")
+	rfc5424Formater.SetProcID("")
+
+	got, err := rfc5424Formater.FormatRecord(newTestSyslogRecord(levels.DEBUG, ""))
+	if err != nil {
+		t.Fatalf("FormatRecord() error = %v", err)
+	}
+
+	if want := "<15>1 2024-03-05T14:03:09.120000Z - my_app - - -"; got != want {
+		t.Errorf("FormatRecord() = %q, want %q", got, want)
+	}
+}
+
+// `TestRFC3164Formater_FormatRecord` tests the format of RFC 3164 messages.
+func TestRFC3164Formater_FormatRecord(t *testing.T) {
+	t.Parallel()
+
+	rfc3164Formater := formater.NewRFC3164Formater()
+	rfc3164Formater.SetFacility(formater.FacilityDaemon)
+	rfc3164Formater.SetHostname("host")
+	rfc3164Formater.SetAppName("app")
+	rfc3164Formater.SetProcID("42")
+
+	got, err := rfc3164Formater.FormatRecord(
+		newTestSyslogRecord(levels.WARN, "low memory", record.Field{Key: "free", Value: "10%"}),
+	)
+	if err != nil {
+		t.Fatalf("FormatRecord() error = %v", err)
+	}
+
+	if want := "<28>Mar  5 14:03:09 host app[42]: low memory free=10%"; got != want {
+		t.Errorf("FormatRecord() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,182 @@
+package handler_test
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/ZertyCraft/GoLogger/handler"
+	"github.com/ZertyCraft/GoLogger/levels"
+)
+
+// `blockingHandler` records messages, waiting for `release` to be closed before the first one.
+type blockingHandler struct {
+	release  chan struct{}
+	started  chan struct{}
+	once     sync.Once
+	mutex    sync.Mutex
+	messages []string
+	flushed  int
+	closed   int
+}
+
+func newBlockingHandler() *blockingHandler {
+	return &blockingHandler{release: make(chan struct{}), started: make(chan struct{})}
+}
+
+func (h *blockingHandler) Log(_ levels.Level, message string) {
+	h.once.Do(func() {
+		close(h.started)
+		<-h.release
+	})
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	h.messages = append(h.messages, message)
+}
+
+func (h *blockingHandler) Flush() error {
+	h.flushed++
+
+	return nil
+}
+
+func (h *blockingHandler) Close() error {
+	h.closed++
+
+	return nil
+}
+
+func (h *blockingHandler) getMessages() []string {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	return h.messages
+}
+
+// TestAsyncHandler_Close test that Close logs all queued messages and closes the target.
+func TestAsyncHandler_Close(t *testing.T) {
+	t.Parallel()
+
+	target := newBlockingHandler()
+	close(target.release)
+
+	asyncHandler := handler.NewAsyncHandler(target, 16)
+
+	for _, message := range []string{"first", "second", "third"} {
+		asyncHandler.Log(levels.DEBUG, message)
+	}
+
+	if err := asyncHandler.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := target.getMessages(), []string{"first", "second", "third"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("messages = %v, want %v", got, want)
+	}
+
+	if target.closed != 1 {
+		t.Errorf("Close calls = %d, want 1", target.closed)
+	}
+
+	if err := asyncHandler.Flush(); err != handler.ErrClosed {
+		t.Errorf("Flush() = %v, want %v", err, handler.ErrClosed)
+	}
+}
+
+// TestAsyncHandler_OverflowPolicies test which messages are kept when the queue is full.
+func TestAsyncHandler_OverflowPolicies(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		policy  handler.OverflowPolicy
+		want    []string
+		dropped uint64
+	}{
+		{
+			name:    "DropNewest",
+			policy:  handler.OverflowDropNewest,
+			want:    []string{"blocking", "debug 1", "debug 2"},
+			dropped: 2,
+		},
+		{
+			name:    "DropOldest",
+			policy:  handler.OverflowDropOldest,
+			want:    []string{"blocking", "debug 3", "error"},
+			dropped: 2,
+		},
+		{
+			name:    "DropBelowLevel",
+			policy:  handler.OverflowDropBelowLevel,
+			want:    []string{"blocking", "debug 1", "debug 2", "error"},
+			dropped: 1,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			target := newBlockingHandler()
+			asyncHandler := handler.NewAsyncHandler(target, 2)
+			asyncHandler.SetOverflowPolicy(test.policy)
+			asyncHandler.SetDropLevel(levels.ERROR)
+
+			// Wait for the worker to block on the first message, so the queue fills up
+			asyncHandler.Log(levels.DEBUG, "blocking")
+			<-target.started
+
+			asyncHandler.Log(levels.DEBUG, "debug 1")
+			asyncHandler.Log(levels.DEBUG, "debug 2")
+			asyncHandler.Log(levels.DEBUG, "debug 3")
+
+			if test.policy == handler.OverflowDropBelowLevel {
+				// The ERROR message blocks until the worker makes room
+				go close(target.release)
+			}
+
+			asyncHandler.Log(levels.ERROR, "error")
+
+			if test.policy != handler.OverflowDropBelowLevel {
+				close(target.release)
+			}
+
+			if err := asyncHandler.Close(); err != nil {
+				t.Fatal(err)
+			}
+
+			if got := target.getMessages(); !reflect.DeepEqual(got, test.want) {
+				t.Errorf("messages = %v, want %v", got, test.want)
+			}
+
+			if got := asyncHandler.Dropped(); got != test.dropped {
+				t.Errorf("Dropped() = %d, want %d", got, test.dropped)
+			}
+		})
+	}
+}
+
+// TestAsyncHandler_NegativeQueueSize test that a queue size below 1 is set to 1 instead of panicking.
+func TestAsyncHandler_NegativeQueueSize(t *testing.T) {
+	t.Parallel()
+
+	target := newBlockingHandler()
+	close(target.release)
+
+	asyncHandler := handler.NewAsyncHandler(target, -1)
+
+	for _, message := range []string{"first", "second"} {
+		asyncHandler.Log(levels.DEBUG, message)
+	}
+
+	if err := asyncHandler.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := target.getMessages(), []string{"first", "second"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("messages = %v, want %v", got, want)
+	}
+}
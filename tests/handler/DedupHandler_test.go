@@ -0,0 +1,69 @@
+package handler_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ZertyCraft/GoLogger/formater"
+	"github.com/ZertyCraft/GoLogger/handler"
+	"github.com/ZertyCraft/GoLogger/handler/handlertest"
+	"github.com/ZertyCraft/GoLogger/levels"
+)
+
+// TestDedupHandler_LogRecord tests that consecutive identical records are collapsed in a StreamHandler file.
+func TestDedupHandler_LogRecord(t *testing.T) {
+	t.Parallel()
+
+	lineFormater := formater.NewLineFormater()
+	lineFormater.SetFormat("%l %m")
+
+	streamHandler := newTestStreamHandler(t, "dedup.log")
+	streamHandler.SetFormater(lineFormater)
+
+	dedupHandler := handler.NewDedupHandler(streamHandler)
+
+	dedupHandler.Log(levels.WARN, "disk full")
+	dedupHandler.Log(levels.WARN, "disk full")
+	dedupHandler.Log(levels.WARN, "disk full")
+	dedupHandler.Log(levels.ERROR, "disk full")
+	dedupHandler.Log(levels.INFO, "disk cleaned")
+	dedupHandler.Log(levels.INFO, "disk cleaned")
+
+	// Flush logs the pending repeat count before flushing the buffer of the stream handler
+	if err := dedupHandler.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "WARN disk full\n" +
+		"WARN last message repeated 2 times\n" +
+		"ERROR disk full\n" +
+		"INFO disk cleaned\n" +
+		"INFO last message repeated 1 times\n"
+
+	if got := readFile(t, streamHandler.GetLogDirectory()+"/dedup.log"); got != want {
+		t.Errorf("file = `%v`, want `%v`", got, want)
+	}
+}
+
+// TestDedupHandler_Timeout tests that the repeat count is logged after the timeout.
+func TestDedupHandler_Timeout(t *testing.T) {
+	t.Parallel()
+
+	recorder := handlertest.NewRecorder()
+	dedupHandler := handler.NewDedupHandler(recorder)
+	dedupHandler.SetTimeout(time.Millisecond)
+
+	dedupHandler.Log(levels.WARN, "retrying")
+	dedupHandler.Log(levels.WARN, "retrying")
+
+	deadline := time.Now().Add(time.Second)
+	for len(recorder.Records()) < 2 {
+		if time.Now().After(deadline) {
+			t.Fatal("repeat count was not logged")
+		}
+
+		time.Sleep(time.Millisecond)
+	}
+
+	recorder.AssertLogged(t, levels.WARN, "last message repeated 1 times")
+}
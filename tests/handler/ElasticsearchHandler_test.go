@@ -0,0 +1,182 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ZertyCraft/GoLogger/handler"
+	"github.com/ZertyCraft/GoLogger/levels"
+	"github.com/ZertyCraft/GoLogger/record"
+)
+
+// bulkAction is an action line of a bulk request.
+type bulkAction struct {
+	Index struct {
+		Index string `json:"_index"`
+	} `json:"index"`
+}
+
+// parseBulk returns the indices and the messages of the documents of a bulk request.
+func parseBulk(t *testing.T, body string) ([]string, []string) {
+	t.Helper()
+
+	lines := strings.Split(strings.TrimSuffix(body, "\n"), "\n")
+	indices := make([]string, 0)
+	messages := make([]string, 0)
+
+	for i := 0; i+1 < len(lines); i += 2 {
+		var action bulkAction
+		if err := json.Unmarshal([]byte(lines[i]), &action); err != nil {
+			t.Fatalf("action %q is not JSON: %v", lines[i], err)
+		}
+
+		var document map[string]any
+		if err := json.Unmarshal([]byte(lines[i+1]), &document); err != nil {
+			t.Fatalf("document %q is not JSON: %v", lines[i+1], err)
+		}
+
+		indices = append(indices, action.Index.Index)
+		messages = append(messages, document["message"].(string))
+	}
+
+	return indices, messages
+}
+
+// TestElasticsearchHandler_PartialRetry tests that only the items rejected with a 429 status are retried.
+func TestElasticsearchHandler_PartialRetry(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mutex  sync.Mutex
+		bodies []string
+	)
+
+	responses := []string{
+		`{"errors":true,"items":[` +
+			`{"index":{"status":201}},` +
+			`{"index":{"status":429,"error":{"type":"es_rejected_execution_exception"}}},` +
+			`{"index":{"status":400,"error":{"type":"mapper_parsing_exception"}}}]}`,
+		`{"errors":false,"items":[{"index":{"status":201}}]}`,
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		body, _ := io.ReadAll(request.Body)
+
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		if request.URL.Path != "/_bulk" || request.Header.Get("Content-Type") != "application/x-ndjson" {
+			http.Error(writer, "unexpected request", http.StatusNotFound)
+
+			return
+		}
+
+		bodies = append(bodies, string(body))
+		writer.Write([]byte(responses[min(len(bodies), len(responses))-1]))
+	}))
+
+	defer server.Close()
+
+	esHandler := handler.NewElasticsearchHandler(server.URL)
+	esHandler.SetFlushInterval(time.Hour)
+	esHandler.SetBackoff(5*time.Millisecond, 20*time.Millisecond)
+
+	var errs []error
+
+	esHandler.SetErrorHandler(func(err error) { errs = append(errs, err) })
+
+	day := time.Date(2026, time.October, 18, 23, 30, 0, 0, time.UTC)
+
+	for i, message := range []string{"indexed", "retried", "rejected"} {
+		rec := record.New(levels.INFO, message)
+		rec.Time = day.Add(time.Duration(i) * time.Hour)
+		esHandler.LogRecord(rec)
+	}
+
+	if err := esHandler.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(bodies) != 2 {
+		t.Fatalf("bodies = %v, want 2 requests", bodies)
+	}
+
+	indices, messages := parseBulk(t, bodies[0])
+	if want := []string{"logs-2026.10.18", "logs-2026.10.19", "logs-2026.10.19"}; !reflect.DeepEqual(indices, want) {
+		t.Errorf("indices = %v, want %v", indices, want)
+	}
+
+	if want := []string{"indexed", "retried", "rejected"}; !reflect.DeepEqual(messages, want) {
+		t.Errorf("messages = %v, want %v", messages, want)
+	}
+
+	if _, messages := parseBulk(t, bodies[1]); !reflect.DeepEqual(messages, []string{"retried"}) {
+		t.Errorf("retried messages = %v, want [retried]", messages)
+	}
+
+	if len(errs) != 1 || !errors.Is(errs[0], handler.ErrDeliveryFailed) || !strings.Contains(errs[0].Error(), "mapper") {
+		t.Errorf("errors = %v, want the rejected record", errs)
+	}
+
+	if esHandler.Dropped() != 1 {
+		t.Errorf("Dropped() = %d, want 1", esHandler.Dropped())
+	}
+}
+
+// TestElasticsearchHandler_Backpressure tests that logging blocks while the queue is full.
+func TestElasticsearchHandler_Backpressure(t *testing.T) {
+	t.Parallel()
+
+	release := make(chan struct{})
+
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, _ *http.Request) {
+		<-release
+		writer.Write([]byte(`{"errors":false,"items":[]}`))
+	}))
+
+	defer server.Close()
+
+	esHandler := handler.NewElasticsearchHandler(server.URL)
+	esHandler.SetBatchSize(1, 1024)
+	esHandler.SetQueueSize(1)
+
+	esHandler.Log(levels.INFO, "in flight")
+	esHandler.Log(levels.INFO, "queued")
+
+	logged := make(chan struct{})
+
+	go func() {
+		esHandler.Log(levels.INFO, "blocked")
+		close(logged)
+	}()
+
+	select {
+	case <-logged:
+		t.Fatal("Log() returned while the queue was full")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(release)
+
+	select {
+	case <-logged:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Log() still blocked after the cluster responded")
+	}
+
+	if err := esHandler.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if esHandler.Dropped() != 0 {
+		t.Errorf("Dropped() = %d, want 0", esHandler.Dropped())
+	}
+}
@@ -0,0 +1,97 @@
+package handler_test
+
+import (
+	"bytes"
+	"regexp"
+	"testing"
+
+	"github.com/ZertyCraft/GoLogger/handler"
+	"github.com/ZertyCraft/GoLogger/levels"
+	"github.com/ZertyCraft/GoLogger/logger"
+	"github.com/ZertyCraft/GoLogger/record"
+)
+
+// TestFilters tests the built-in filters and their combinations.
+func TestFilters(t *testing.T) {
+	t.Parallel()
+
+	rec := record.New(levels.INFO, "GET /health 200")
+	rec.LoggerName = "app.http"
+	rec.Fields = []record.Field{{Key: "path", Value: "/health"}, {Key: "status", Value: 200}}
+
+	tests := []struct {
+		name   string
+		filter handler.Filter
+		want   bool
+	}{
+		{name: "LevelRange", filter: handler.LevelRange(levels.DEBUG, levels.INFO), want: true},
+		{name: "LevelRangeOutside", filter: handler.LevelRange(levels.WARN, levels.CRITICAL), want: false},
+		{name: "MessageContains", filter: handler.MessageContains("/health"), want: true},
+		{name: "MessageMatches", filter: handler.MessageMatches(regexp.MustCompile(`^GET .* 5\d\d$`)), want: false},
+		{name: "FieldEquals", filter: handler.FieldEquals("status", 200), want: true},
+		{name: "FieldEqualsOtherValue", filter: handler.FieldEquals("status", 500), want: false},
+		{name: "FieldEqualsMissing", filter: handler.FieldEquals("user", nil), want: false},
+		{name: "LoggerNamePrefix", filter: handler.LoggerNamePrefix("app."), want: true},
+		{
+			name:   "And",
+			filter: handler.And(handler.LoggerNamePrefix("app."), handler.FieldEquals("status", 500)),
+			want:   false,
+		},
+		{
+			name:   "Or",
+			filter: handler.Or(handler.LoggerNamePrefix("db."), handler.FieldEquals("status", 200)),
+			want:   true,
+		},
+		{name: "Not", filter: handler.Not(handler.FieldEquals("path", "/health")), want: false},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := test.filter.Allow(rec); got != test.want {
+				t.Errorf("Allow() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+// TestFilterHandler_LogRecord tests that the FilterHandler drops health check records logged through a logger.
+func TestFilterHandler_LogRecord(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	filterHandler := handler.NewFilterHandler(
+		newTestConsoleHandler(&buf),
+		handler.Not(handler.FieldEquals("path", "/health")),
+	)
+
+	log := logger.NewLogger()
+	log.AddHandler(filterHandler)
+
+	log.Info("health check", record.Field{Key: "path", Value: "/health"})
+	log.With(record.Field{Key: "path", Value: "/users"}).Info("users")
+
+	if got := buf.String(); got != "INFO users\n" {
+		t.Errorf("Log() = `%v`, want `INFO users\\n`", got)
+	}
+}
+
+// TestBaseHandler_AddFilter tests filters attached directly to a handler.
+func TestBaseHandler_AddFilter(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	consoleHandler := newTestConsoleHandler(&buf)
+	consoleHandler.AddFilter(handler.MessageContains("keep"))
+
+	consoleHandler.Log(levels.INFO, "keep this")
+	consoleHandler.Log(levels.INFO, "drop this")
+
+	if got := buf.String(); got != "INFO keep this\n" {
+		t.Errorf("Log() = `%v`, want `INFO keep this\\n`", got)
+	}
+}
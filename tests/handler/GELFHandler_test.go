@@ -0,0 +1,226 @@
+package handler_test
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"encoding/json"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ZertyCraft/GoLogger/handler"
+	"github.com/ZertyCraft/GoLogger/levels"
+)
+
+// readDatagrams reads the next `count` datagrams of the connection.
+func readDatagrams(t *testing.T, conn net.PacketConn, count int) [][]byte {
+	t.Helper()
+
+	datagrams := make([][]byte, 0, count)
+	buffer := make([]byte, 65536)
+
+	for len(datagrams) < count {
+		if err := conn.SetReadDeadline(time.Now().Add(5 * time.Second)); err != nil {
+			t.Fatal(err)
+		}
+
+		size, _, err := conn.ReadFrom(buffer)
+		if err != nil {
+			t.Fatalf("received %d datagrams, want %d: %v", len(datagrams), count, err)
+		}
+
+		datagrams = append(datagrams, append([]byte(nil), buffer[:size]...))
+	}
+
+	return datagrams
+}
+
+// decodeGELF decodes a GELF message, failing the test if it is not JSON.
+func decodeGELF(t *testing.T, data []byte) map[string]any {
+	t.Helper()
+
+	var message map[string]any
+	if err := json.Unmarshal(data, &message); err != nil {
+		t.Fatalf("message %q is not JSON: %v", data, err)
+	}
+
+	return message
+}
+
+// TestGELFHandler_UDPChunking tests that large messages are compressed and split into chunks.
+func TestGELFHandler_UDPChunking(t *testing.T) {
+	t.Parallel()
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	gelfHandler := handler.NewGELFHandler("udp", conn.LocalAddr().String())
+	gelfHandler.SetCompression(handler.GELFCompressionZlib)
+	gelfHandler.SetChunkSize(512)
+	gelfHandler.SetErrorHandler(func(err error) { t.Error(err) })
+
+	// Random-looking content, so that the compressed message still needs several chunks
+	var builder strings.Builder
+	for i := 0; builder.Len() < 4000; i++ {
+		builder.WriteString(time.Duration(i * 7919 * 104729).String())
+	}
+
+	message := builder.String()
+	gelfHandler.Log(levels.WARN, message)
+
+	if err := gelfHandler.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	first := readDatagrams(t, conn, 1)[0]
+	if !bytes.Equal(first[:2], []byte{0x1e, 0x0f}) {
+		t.Fatalf("datagram starts with %x, want the chunk magic bytes", first[:2])
+	}
+
+	count := int(first[11])
+	chunks := append([][]byte{first}, readDatagrams(t, conn, count-1)...)
+	payloads := make([][]byte, count)
+
+	for _, chunk := range chunks {
+		if len(chunk) > 512 || !bytes.Equal(chunk[2:10], first[2:10]) || int(chunk[11]) != count {
+			t.Fatalf("chunk header %x, want the message id and count of the first chunk", chunk[:12])
+		}
+
+		payloads[chunk[10]] = chunk[12:]
+	}
+
+	reader, err := zlib.NewReader(bytes.NewReader(bytes.Join(payloads, nil)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if decoded := decodeGELF(t, data); decoded["short_message"] != message || decoded["level"] != float64(4) {
+		t.Errorf("message = %v, want the WARN message", decoded)
+	}
+}
+
+// TestGELFHandler_SmallChunkSize tests that a chunk size smaller than the chunk header is raised,
+// so that messages are still split into chunks of one byte of payload.
+func TestGELFHandler_SmallChunkSize(t *testing.T) {
+	t.Parallel()
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	gelfHandler := handler.NewGELFHandler("udp", conn.LocalAddr().String())
+	gelfHandler.SetChunkSize(0)
+	gelfHandler.SetErrorHandler(func(err error) { t.Error(err) })
+
+	if got := gelfHandler.GetChunkSize(); got != 13 {
+		t.Errorf("GetChunkSize() = %d, want 13", got)
+	}
+
+	gelfHandler.Log(levels.INFO, "tiny")
+
+	if err := gelfHandler.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	first := readDatagrams(t, conn, 1)[0]
+	count := int(first[11])
+	chunks := append([][]byte{first}, readDatagrams(t, conn, count-1)...)
+	payloads := make([][]byte, count)
+
+	for _, chunk := range chunks {
+		if len(chunk) != 13 {
+			t.Fatalf("chunk of %d bytes, want 13", len(chunk))
+		}
+
+		payloads[chunk[10]] = chunk[12:]
+	}
+
+	if decoded := decodeGELF(t, bytes.Join(payloads, nil)); decoded["short_message"] != "tiny" {
+		t.Errorf("message = %v, want the tiny message", decoded)
+	}
+}
+
+// TestGELFHandler_UDPGzip tests that small messages are sent in one compressed datagram.
+func TestGELFHandler_UDPGzip(t *testing.T) {
+	t.Parallel()
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	gelfHandler := handler.NewGELFHandler("udp", conn.LocalAddr().String())
+	gelfHandler.SetCompression(handler.GELFCompressionGzip)
+	gelfHandler.Log(levels.INFO, "small")
+
+	if err := gelfHandler.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	reader, err := gzip.NewReader(bytes.NewReader(readDatagrams(t, conn, 1)[0]))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if decoded := decodeGELF(t, data); decoded["short_message"] != "small" {
+		t.Errorf("message = %v, want the small message", decoded)
+	}
+}
+
+// TestGELFHandler_TCP tests that messages are delimited with a null byte over TCP.
+func TestGELFHandler_TCP(t *testing.T) {
+	t.Parallel()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	gelfHandler := handler.NewGELFHandler("tcp", listener.Addr().String())
+	gelfHandler.Log(levels.INFO, "first")
+	gelfHandler.Log(levels.ERROR, "second")
+
+	if err := gelfHandler.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	conn, err := listener.Accept()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	reader := bufio.NewReader(conn)
+
+	for _, want := range []string{"first", "second"} {
+		data, err := reader.ReadBytes(0)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if decoded := decodeGELF(t, data[:len(data)-1]); decoded["short_message"] != want {
+			t.Errorf("message = %v, want %q", decoded, want)
+		}
+	}
+}
@@ -0,0 +1,261 @@
+package handler_test
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ZertyCraft/GoLogger/formater"
+	"github.com/ZertyCraft/GoLogger/handler"
+	"github.com/ZertyCraft/GoLogger/levels"
+)
+
+// httpRequests records the requests received by a test server.
+type httpRequests struct {
+	mutex    sync.Mutex
+	headers  []http.Header
+	bodies   []string
+	statuses []int // The statuses of the next responses, 200 once exhausted
+}
+
+// ServeHTTP records the request, decompressing its body if needed, and responds with the next status.
+func (requests *httpRequests) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
+	var reader io.Reader = request.Body
+
+	if request.Header.Get("Content-Encoding") == "gzip" {
+		gzipReader, err := gzip.NewReader(request.Body)
+		if err != nil {
+			http.Error(writer, err.Error(), http.StatusBadRequest)
+
+			return
+		}
+
+		reader = gzipReader
+	}
+
+	body, _ := io.ReadAll(reader)
+
+	requests.mutex.Lock()
+	defer requests.mutex.Unlock()
+
+	requests.headers = append(requests.headers, request.Header.Clone())
+	requests.bodies = append(requests.bodies, string(body))
+
+	status := http.StatusOK
+	if len(requests.statuses) > 0 {
+		status = requests.statuses[0]
+		requests.statuses = requests.statuses[1:]
+	}
+
+	writer.WriteHeader(status)
+}
+
+// Bodies returns the bodies of the received requests.
+func (requests *httpRequests) Bodies() []string {
+	requests.mutex.Lock()
+	defer requests.mutex.Unlock()
+
+	return append([]string(nil), requests.bodies...)
+}
+
+// newTestHTTPHandler returns an HTTPHandler posting `%m` messages to the server, with short delays.
+func newTestHTTPHandler(t *testing.T, requests *httpRequests) *handler.HTTPHandler {
+	t.Helper()
+
+	server := httptest.NewServer(requests)
+	t.Cleanup(server.Close)
+
+	lineFormater := formater.NewLineFormater()
+	lineFormater.SetFormat(`"%m"`)
+
+	httpHandler := handler.NewHTTPHandler(server.URL)
+	httpHandler.SetFormater(lineFormater)
+	httpHandler.SetBackoff(5*time.Millisecond, 20*time.Millisecond)
+	httpHandler.SetFlushInterval(time.Hour)
+
+	return httpHandler
+}
+
+// TestHTTPHandler_BatchCount tests that records are sent in batches of the maximum count, as JSON arrays.
+func TestHTTPHandler_BatchCount(t *testing.T) {
+	t.Parallel()
+
+	requests := &httpRequests{}
+	httpHandler := newTestHTTPHandler(t, requests)
+	httpHandler.SetBatchSize(2, 1024)
+
+	for _, message := range []string{"a", "b", "c"} {
+		httpHandler.Log(levels.INFO, message)
+	}
+
+	if err := httpHandler.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{`["a","b"]`, `["c"]`}
+	if got := requests.Bodies(); !reflect.DeepEqual(got, want) {
+		t.Errorf("bodies = %v, want %v", got, want)
+	}
+
+	if got := requests.headers[0].Get("Content-Type"); got != "application/json" {
+		t.Errorf("Content-Type = %q, want %q", got, "application/json")
+	}
+}
+
+// TestHTTPHandler_FlushInterval tests that records are sent after the flush interval.
+func TestHTTPHandler_FlushInterval(t *testing.T) {
+	t.Parallel()
+
+	requests := &httpRequests{}
+	httpHandler := newTestHTTPHandler(t, requests)
+	httpHandler.SetFlushInterval(20 * time.Millisecond)
+
+	defer httpHandler.Close()
+
+	httpHandler.Log(levels.INFO, "late")
+
+	deadline := time.Now().Add(5 * time.Second)
+	for len(requests.Bodies()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	if got := requests.Bodies(); !reflect.DeepEqual(got, []string{`["late"]`}) {
+		t.Errorf("bodies = %v, want [[\"late\"]]", got)
+	}
+}
+
+// TestHTTPHandler_NDJSONGzipHeaders tests the NDJSON encoding, the gzip compression and the custom headers.
+func TestHTTPHandler_NDJSONGzipHeaders(t *testing.T) {
+	t.Parallel()
+
+	requests := &httpRequests{}
+	httpHandler := newTestHTTPHandler(t, requests)
+	httpHandler.SetFormater(formater.NewJSONFormater())
+	httpHandler.SetEncoding(handler.HTTPEncodingNDJSON)
+	httpHandler.SetGzip(true)
+	httpHandler.SetHeader("Authorization", "Bearer token")
+
+	httpHandler.Log(levels.WARN, "first")
+	httpHandler.Log(levels.ERROR, "second")
+
+	if err := httpHandler.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	bodies := requests.Bodies()
+	if len(bodies) != 1 {
+		t.Fatalf("bodies = %v, want 1 body", bodies)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(bodies[0], "\n"), "\n")
+	messages := make([]string, 0, len(lines))
+
+	for _, line := range lines {
+		var decoded map[string]any
+		if err := json.Unmarshal([]byte(line), &decoded); err != nil {
+			t.Fatalf("line %q is not JSON: %v", line, err)
+		}
+
+		messages = append(messages, decoded["level"].(string)+" "+decoded["message"].(string))
+	}
+
+	if want := []string{"WARN first", "ERROR second"}; !reflect.DeepEqual(messages, want) {
+		t.Errorf("messages = %v, want %v", messages, want)
+	}
+
+	headers := requests.headers[0]
+	if headers.Get("Authorization") != "Bearer token" || headers.Get("Content-Type") != "application/x-ndjson" {
+		t.Errorf("headers = %v, want the authorization and the NDJSON content type", headers)
+	}
+}
+
+// TestHTTPHandler_Retry tests that batches are retried on 5xx statuses and not on 4xx statuses.
+func TestHTTPHandler_Retry(t *testing.T) {
+	t.Parallel()
+
+	requests := &httpRequests{statuses: []int{http.StatusServiceUnavailable, http.StatusOK, http.StatusBadRequest}}
+	httpHandler := newTestHTTPHandler(t, requests)
+
+	var errs []error
+
+	httpHandler.SetErrorHandler(func(err error) { errs = append(errs, err) })
+
+	httpHandler.Log(levels.INFO, "retried")
+
+	if err := httpHandler.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	httpHandler.Log(levels.INFO, "rejected")
+
+	if err := httpHandler.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{`["retried"]`, `["retried"]`, `["rejected"]`}
+	if got := requests.Bodies(); !reflect.DeepEqual(got, want) {
+		t.Errorf("bodies = %v, want %v", got, want)
+	}
+
+	if len(errs) != 1 || !errors.Is(errs[0], handler.ErrDeliveryFailed) {
+		t.Errorf("errors = %v, want one delivery failure", errs)
+	}
+
+	if httpHandler.Dropped() != 1 {
+		t.Errorf("Dropped() = %d, want 1", httpHandler.Dropped())
+	}
+}
+
+// TestHTTPHandler_ZeroQueueSize tests that a queue size below 1 neither panics nor blocks forever,
+// whatever the overflow policy.
+func TestHTTPHandler_ZeroQueueSize(t *testing.T) {
+	t.Parallel()
+
+	for _, policy := range []handler.OverflowPolicy{handler.OverflowDropOldest, handler.OverflowBlock} {
+		requests := &httpRequests{}
+		httpHandler := newTestHTTPHandler(t, requests)
+		httpHandler.SetQueueSize(0)
+		httpHandler.SetOverflowPolicy(policy)
+
+		httpHandler.Log(levels.INFO, "first")
+		httpHandler.Log(levels.INFO, "second")
+
+		if err := httpHandler.Close(); err != nil {
+			t.Fatal(err)
+		}
+
+		if got := strings.Join(requests.Bodies(), ""); !strings.Contains(got, `"second"`) {
+			t.Errorf("policy %d: bodies = %v, want the last record", policy, requests.Bodies())
+		}
+	}
+}
+
+// TestHTTPHandler_ZeroBatchSize tests that a batch size below 1 still sends the records, one per batch.
+func TestHTTPHandler_ZeroBatchSize(t *testing.T) {
+	t.Parallel()
+
+	requests := &httpRequests{}
+	httpHandler := newTestHTTPHandler(t, requests)
+	httpHandler.SetBatchSize(0, 0)
+	httpHandler.SetFlushTimeout(time.Second)
+
+	httpHandler.Log(levels.INFO, "first")
+	httpHandler.Log(levels.INFO, "second")
+
+	if err := httpHandler.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{`["first"]`, `["second"]`}
+	if got := requests.Bodies(); !reflect.DeepEqual(got, want) {
+		t.Errorf("bodies = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,241 @@
+//go:build linux
+
+package handler_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/ZertyCraft/GoLogger/handler"
+	"github.com/ZertyCraft/GoLogger/levels"
+	"github.com/ZertyCraft/GoLogger/logger"
+	"github.com/ZertyCraft/GoLogger/record"
+)
+
+// listenJournald listens on a local journal socket, returning the connection and the handler sending to it.
+func listenJournald(t *testing.T) (*net.UnixConn, *handler.JournaldHandler) {
+	t.Helper()
+
+	// Unix socket paths are limited in length, so avoid the long test directory
+	directory, err := os.MkdirTemp("", "journal")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() { os.RemoveAll(directory) })
+
+	socketPath := filepath.Join(directory, "socket")
+
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: socketPath, Net: "unixgram"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() { conn.Close() })
+
+	journaldHandler := handler.NewJournaldHandler()
+	journaldHandler.SetSocketPath(socketPath)
+	journaldHandler.SetIdentifier("app")
+	journaldHandler.SetErrorHandler(func(err error) { t.Error(err) })
+
+	t.Cleanup(func() { journaldHandler.Close() })
+
+	return conn, journaldHandler
+}
+
+// receiveJournald reads the next entry sent to the journal socket, from the datagram or from the sent file.
+func receiveJournald(t *testing.T, conn *net.UnixConn) map[string]string {
+	t.Helper()
+
+	if err := conn.SetReadDeadline(time.Now().Add(5 * time.Second)); err != nil {
+		t.Fatal(err)
+	}
+
+	buffer := make([]byte, 64*1024)
+	oob := make([]byte, syscall.CmsgSpace(4))
+
+	size, oobSize, _, _, err := conn.ReadMsgUnix(buffer, oob)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	payload := buffer[:size]
+
+	if oobSize > 0 {
+		payload = readJournaldFile(t, oob[:oobSize])
+	}
+
+	return parseJournald(t, payload)
+}
+
+// readJournaldFile reads the payload from the file descriptor sent in the control message.
+func readJournaldFile(t *testing.T, oob []byte) []byte {
+	t.Helper()
+
+	messages, err := syscall.ParseSocketControlMessage(oob)
+	if err != nil || len(messages) != 1 {
+		t.Fatalf("ParseSocketControlMessage() = %v, %v", messages, err)
+	}
+
+	fds, err := syscall.ParseUnixRights(&messages[0])
+	if err != nil || len(fds) != 1 {
+		t.Fatalf("ParseUnixRights() = %v, %v", fds, err)
+	}
+
+	file := os.NewFile(uintptr(fds[0]), "payload")
+	defer file.Close()
+
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+
+	payload, err := io.ReadAll(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return payload
+}
+
+// parseJournald parses an entry of the native protocol.
+func parseJournald(t *testing.T, payload []byte) map[string]string {
+	t.Helper()
+
+	fields := make(map[string]string)
+
+	for len(payload) > 0 {
+		end := bytes.IndexByte(payload, '\n')
+		if end < 0 {
+			t.Fatalf("unterminated field %q", payload)
+		}
+
+		line := string(payload[:end])
+		payload = payload[end+1:]
+
+		if name, value, ok := strings.Cut(line, "="); ok {
+			fields[name] = value
+
+			continue
+		}
+
+		size := binary.LittleEndian.Uint64(payload)
+		fields[line] = string(payload[8 : 8+size])
+		payload = payload[8+size+1:]
+	}
+
+	return fields
+}
+
+// TestJournaldHandler_Fields tests the fields of the entries.
+func TestJournaldHandler_Fields(t *testing.T) {
+	t.Parallel()
+
+	conn, journaldHandler := listenJournald(t)
+
+	testLogger := logger.NewLogger()
+	testLogger.SetName("db")
+	testLogger.SetReportCaller(true)
+	testLogger.AddHandler(journaldHandler)
+
+	_, file, line, _ := runtime.Caller(0)
+	testLogger.Warning("slow\nquery", record.Field{Key: "request-id", Value: 7}, record.Field{Key: "_secret", Value: 1})
+
+	entry := receiveJournald(t, conn)
+
+	want := map[string]string{
+		"MESSAGE":           "slow\nquery",
+		"PRIORITY":          "4",
+		"SYSLOG_IDENTIFIER": "app",
+		"LOGGER_NAME":       "db",
+		"CODE_FILE":         file,
+		"CODE_LINE":         strconv.Itoa(line + 1),
+		"REQUEST_ID":        "7",
+		"FIELD_SECRET":      "1",
+	}
+
+	for name, value := range want {
+		if entry[name] != value {
+			t.Errorf("%s = %q, want %q", name, entry[name], value)
+		}
+	}
+
+	if !strings.HasSuffix(entry["CODE_FUNC"], "TestJournaldHandler_Fields") {
+		t.Errorf("CODE_FUNC = %q, want the test function", entry["CODE_FUNC"])
+	}
+}
+
+// TestJournaldHandler_ReservedFields tests that fields named like the fields of the handler
+// or like trusted fields are prefixed instead of replacing them.
+func TestJournaldHandler_ReservedFields(t *testing.T) {
+	t.Parallel()
+
+	conn, journaldHandler := listenJournald(t)
+
+	rec := record.New(levels.ERROR, "disk full")
+	rec.Fields = []record.Field{
+		{Key: "message", Value: "user message"},
+		{Key: "priority", Value: 7},
+		{Key: "syslog_identifier", Value: "other"},
+		{Key: "code_file", Value: "main.go"},
+		{Key: "_pid", Value: 1},
+		{Key: "2fa", Value: true},
+	}
+
+	journaldHandler.LogRecord(rec)
+
+	entry := receiveJournald(t, conn)
+
+	want := map[string]string{
+		"MESSAGE":                 "disk full",
+		"PRIORITY":                "3",
+		"SYSLOG_IDENTIFIER":       "app",
+		"FIELD_MESSAGE":           "user message",
+		"FIELD_PRIORITY":          "7",
+		"FIELD_SYSLOG_IDENTIFIER": "other",
+		"FIELD_CODE_FILE":         "main.go",
+		"FIELD_PID":               "1",
+		"FIELD_2FA":               "true",
+	}
+
+	for name, value := range want {
+		if entry[name] != value {
+			t.Errorf("%s = %q, want %q", name, entry[name], value)
+		}
+	}
+
+	for _, name := range []string{"CODE_FILE", "_PID", "PID"} {
+		if value, ok := entry[name]; ok {
+			t.Errorf("%s = %q, want no such field", name, value)
+		}
+	}
+}
+
+// TestJournaldHandler_LargePayload tests that payloads too large for a datagram are sent through a file.
+func TestJournaldHandler_LargePayload(t *testing.T) {
+	t.Parallel()
+
+	conn, journaldHandler := listenJournald(t)
+
+	message := strings.Repeat("x", 4*1024*1024)
+	journaldHandler.Log(levels.ERROR, message)
+
+	entry := receiveJournald(t, conn)
+
+	if entry["MESSAGE"] != message {
+		t.Errorf("len(MESSAGE) = %d, want %d", len(entry["MESSAGE"]), len(message))
+	}
+
+	if entry["PRIORITY"] != "3" {
+		t.Errorf("PRIORITY = %q, want %q", entry["PRIORITY"], "3")
+	}
+}
@@ -0,0 +1,129 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/ZertyCraft/GoLogger/formater"
+	"github.com/ZertyCraft/GoLogger/handler"
+	"github.com/ZertyCraft/GoLogger/levels"
+	"github.com/ZertyCraft/GoLogger/record"
+)
+
+// lokiStream is a stream of a Loki push request.
+type lokiStream struct {
+	Stream map[string]string `json:"stream"`
+	Values [][2]string       `json:"values"`
+}
+
+// lokiPush is the body of a Loki push request.
+type lokiPush struct {
+	Streams []lokiStream `json:"streams"`
+}
+
+// TestLokiHandler_Streams tests that records are grouped into streams by labels and sorted by time.
+func TestLokiHandler_Streams(t *testing.T) {
+	t.Parallel()
+
+	requests := &httpRequests{}
+	server := httptest.NewServer(requests)
+
+	defer server.Close()
+
+	lineFormater := formater.NewLineFormater()
+	lineFormater.SetFormat("%m")
+
+	lokiHandler := handler.NewLokiHandler(server.URL + "/loki/api/v1/push")
+	lokiHandler.SetFormater(lineFormater)
+	lokiHandler.SetFlushInterval(time.Hour)
+	lokiHandler.SetLabel("app", "shop")
+	lokiHandler.SetFieldLabels("region")
+	lokiHandler.SetErrorHandler(func(err error) { t.Error(err) })
+
+	base := time.Unix(1700000000, 0)
+	newRecord := func(level levels.Level, message string, offset time.Duration, fields ...record.Field) record.Record {
+		rec := record.New(level, message)
+		rec.Time = base.Add(offset)
+		rec.LoggerName = "api"
+		rec.Fields = fields
+
+		return rec
+	}
+
+	eu := record.Field{Key: "region", Value: "eu"}
+	lokiHandler.LogRecord(newRecord(levels.INFO, "second", 2*time.Second, eu))
+	lokiHandler.LogRecord(newRecord(levels.ERROR, "failure", 3*time.Second, eu))
+	lokiHandler.LogRecord(newRecord(levels.INFO, "first", time.Second, eu))
+	lokiHandler.LogRecord(newRecord(levels.INFO, "elsewhere", 0, record.Field{Key: "region", Value: "us"}))
+
+	if err := lokiHandler.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	bodies := requests.Bodies()
+	if len(bodies) != 1 {
+		t.Fatalf("bodies = %v, want 1 body", bodies)
+	}
+
+	var push lokiPush
+	if err := json.Unmarshal([]byte(bodies[0]), &push); err != nil {
+		t.Fatal(err)
+	}
+
+	timestamp := func(offset time.Duration) string {
+		return strconv.FormatInt(base.Add(offset).UnixNano(), 10)
+	}
+
+	labels := func(level string, region string) map[string]string {
+		return map[string]string{"app": "shop", "level": level, "logger": "api", "region": region}
+	}
+
+	want := lokiPush{Streams: []lokiStream{
+		{labels("info", "eu"), [][2]string{{timestamp(time.Second), "first"}, {timestamp(2 * time.Second), "second"}}},
+		{labels("error", "eu"), [][2]string{{timestamp(3 * time.Second), "failure"}}},
+		{labels("info", "us"), [][2]string{{timestamp(0), "elsewhere"}}},
+	}}
+
+	if !reflect.DeepEqual(push, want) {
+		t.Errorf("push = %+v, want %+v", push, want)
+	}
+}
+
+// TestLokiHandler_ReservedLabels tests that static and field labels never replace the level and logger labels.
+func TestLokiHandler_ReservedLabels(t *testing.T) {
+	t.Parallel()
+
+	requests := &httpRequests{}
+	server := httptest.NewServer(requests)
+
+	defer server.Close()
+
+	lokiHandler := handler.NewLokiHandler(server.URL + "/loki/api/v1/push")
+	lokiHandler.SetFlushInterval(time.Hour)
+	lokiHandler.SetLabel("logger", "static")
+	lokiHandler.SetFieldLabels("level")
+	lokiHandler.SetErrorHandler(func(err error) { t.Error(err) })
+
+	rec := record.New(levels.ERROR, "failure")
+	rec.LoggerName = "api"
+	rec.Fields = []record.Field{{Key: "level", Value: "debug"}}
+	lokiHandler.LogRecord(rec)
+
+	if err := lokiHandler.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	var push lokiPush
+	if err := json.Unmarshal([]byte(requests.Bodies()[0]), &push); err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]string{"level": "error", "logger": "api", "field_level": "debug", "field_logger": "static"}
+	if got := push.Streams[0].Stream; !reflect.DeepEqual(got, want) {
+		t.Errorf("labels = %v, want %v", got, want)
+	}
+}
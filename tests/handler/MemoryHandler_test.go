@@ -0,0 +1,105 @@
+package handler_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ZertyCraft/GoLogger/handler"
+	"github.com/ZertyCraft/GoLogger/levels"
+	"github.com/ZertyCraft/GoLogger/record"
+)
+
+// TestMemoryHandler_Trigger tests that the last records are logged only when a trigger record arrives.
+func TestMemoryHandler_Trigger(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	memoryHandler := handler.NewMemoryHandler(newTestConsoleHandler(&buf), 2)
+	memoryHandler.SetTriggerLevel(levels.ERROR)
+
+	memoryHandler.Log(levels.DEBUG, "discarded")
+	memoryHandler.Log(levels.DEBUG, "first")
+	memoryHandler.Log(levels.INFO, "second")
+
+	if got := buf.String(); got != "" {
+		t.Errorf("Log() before trigger = `%v`, want ``", got)
+	}
+
+	memoryHandler.Log(levels.ERROR, "trigger")
+	memoryHandler.Log(levels.DEBUG, "after")
+
+	if got, want := buf.String(), "DEBUG first\nINFO second\nERROR trigger\n"; got != want {
+		t.Errorf("Log() = `%v`, want `%v`", got, want)
+	}
+}
+
+// TestMemoryHandler_KeyField tests that a trigger logs only the records of its key.
+func TestMemoryHandler_KeyField(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	memoryHandler := handler.NewMemoryHandler(newTestConsoleHandler(&buf), 10)
+	memoryHandler.SetKeyField("request_id")
+
+	logWithRequest := func(level levels.Level, message string, requestID string) {
+		rec := record.New(level, message)
+		rec.Fields = []record.Field{{Key: "request_id", Value: requestID}}
+		memoryHandler.LogRecord(rec)
+	}
+
+	logWithRequest(levels.DEBUG, "request 1", "1")
+	logWithRequest(levels.DEBUG, "request 2", "2")
+	logWithRequest(levels.ERROR, "request 2 failed", "2")
+
+	memoryHandler.Discard("1")
+	logWithRequest(levels.ERROR, "request 1 failed", "1")
+
+	if got, want := buf.String(), "DEBUG request 2\nERROR request 2 failed\nERROR request 1 failed\n"; got != want {
+		t.Errorf("Log() = `%v`, want `%v`", got, want)
+	}
+}
+
+// TestMemoryHandler_MaxKeys tests that the oldest buffer is discarded when there are too many keys,
+// and that no buffer is discarded without limit.
+func TestMemoryHandler_MaxKeys(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		maxKeys int
+		want    string
+	}{
+		{"limited", 2, "ERROR request 1 failed\nDEBUG request 3\nERROR request 3 failed\n"},
+		{"unlimited", 0, "DEBUG request 1\nERROR request 1 failed\nDEBUG request 3\nERROR request 3 failed\n"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			var buf bytes.Buffer
+
+			memoryHandler := handler.NewMemoryHandler(newTestConsoleHandler(&buf), 10)
+			memoryHandler.SetKeyField("request_id")
+			memoryHandler.SetMaxKeys(test.maxKeys)
+
+			logWithRequest := func(level levels.Level, message string, requestID string) {
+				rec := record.New(level, message)
+				rec.Fields = []record.Field{{Key: "request_id", Value: requestID}}
+				memoryHandler.LogRecord(rec)
+			}
+
+			logWithRequest(levels.DEBUG, "request 1", "1")
+			logWithRequest(levels.DEBUG, "request 2", "2")
+			logWithRequest(levels.DEBUG, "request 3", "3")
+			logWithRequest(levels.ERROR, "request 1 failed", "1")
+			logWithRequest(levels.ERROR, "request 3 failed", "3")
+
+			if got := buf.String(); got != test.want {
+				t.Errorf("Log() = `%v`, want `%v`", got, test.want)
+			}
+		})
+	}
+}
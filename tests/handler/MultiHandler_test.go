@@ -0,0 +1,93 @@
+package handler_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ZertyCraft/GoLogger/formater"
+	"github.com/ZertyCraft/GoLogger/handler"
+	"github.com/ZertyCraft/GoLogger/levels"
+)
+
+// newTestConsoleHandler returns a ConsoleHandler writing `%l %m` lines at DEBUG level to the given buffer.
+func newTestConsoleHandler(buf *bytes.Buffer) *handler.ConsoleHandler {
+	lineFormater := formater.NewLineFormater()
+	lineFormater.SetFormat("%l %m")
+
+	consoleHandler := handler.NewConsoleHandler(buf)
+	consoleHandler.SetFormater(lineFormater)
+	consoleHandler.SetLevel(levels.DEBUG)
+
+	return consoleHandler
+}
+
+// TestMultiHandler_Log test that the MultiHandler logs messages with all its handlers.
+func TestMultiHandler_Log(t *testing.T) {
+	t.Parallel()
+
+	var first, second bytes.Buffer
+
+	firstHandler := newTestConsoleHandler(&first)
+	multiHandler := handler.NewMultiHandler(firstHandler, newTestConsoleHandler(&second))
+
+	multiHandler.Log(levels.INFO, "both")
+	multiHandler.RemoveHandler(firstHandler)
+	multiHandler.Log(levels.INFO, "second only")
+
+	if got := first.String(); got != "INFO both\n" {
+		t.Errorf("first handler = `%v`, want `INFO both\\n`", got)
+	}
+
+	if got := second.String(); got != "INFO both\nINFO second only\n" {
+		t.Errorf("second handler = `%v`, want `INFO both\\nINFO second only\\n`", got)
+	}
+}
+
+// TestLevelRouter_Log test that the LevelRouter logs each message with exactly one handler.
+func TestLevelRouter_Log(t *testing.T) {
+	t.Parallel()
+
+	var low, high bytes.Buffer
+
+	levelRouter := handler.NewLevelRouter()
+	levelRouter.AddRoute(levels.WARN, newTestConsoleHandler(&high))
+	levelRouter.AddRoute(levels.DEBUG, newTestConsoleHandler(&low))
+
+	levelRouter.Log(levels.DEBUG, "debug")
+	levelRouter.Log(levels.INFO, "info")
+	levelRouter.Log(levels.WARN, "warn")
+	levelRouter.Log(levels.CRITICAL, "critical")
+
+	if got := low.String(); got != "DEBUG debug\nINFO info\n" {
+		t.Errorf("low handler = `%v`, want `DEBUG debug\\nINFO info\\n`", got)
+	}
+
+	if got := high.String(); got != "WARN warn\nCRITICAL critical\n" {
+		t.Errorf("high handler = `%v`, want `WARN warn\\nCRITICAL critical\\n`", got)
+	}
+}
+
+// TestMultiHandler_Close test that closing a MultiHandler closes the nested handlers.
+func TestMultiHandler_Close(t *testing.T) {
+	t.Parallel()
+
+	target := newBlockingHandler()
+	close(target.release)
+
+	levelRouter := handler.NewLevelRouter()
+	levelRouter.AddRoute(levels.DEBUG, target)
+
+	multiHandler := handler.NewMultiHandler(levelRouter)
+
+	if err := multiHandler.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := multiHandler.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if target.flushed != 1 || target.closed != 1 {
+		t.Errorf("Flush and Close calls = %d and %d, want 1 and 1", target.flushed, target.closed)
+	}
+}
@@ -0,0 +1,266 @@
+package handler_test
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ZertyCraft/GoLogger/formater"
+	"github.com/ZertyCraft/GoLogger/handler"
+	"github.com/ZertyCraft/GoLogger/levels"
+)
+
+// newTestNetworkHandler returns a NetworkHandler sending `%l %m` messages, with short timeouts.
+func newTestNetworkHandler(network string, address string) *handler.NetworkHandler {
+	lineFormater := formater.NewLineFormater()
+	lineFormater.SetFormat("%l %m")
+
+	networkHandler := handler.NewNetworkHandler(network, address)
+	networkHandler.SetFormater(lineFormater)
+	networkHandler.SetBackoff(5*time.Millisecond, 20*time.Millisecond)
+	networkHandler.SetFlushTimeout(5 * time.Second)
+	networkHandler.SetErrorHandler(func(error) {})
+
+	return networkHandler
+}
+
+// acceptLines accepts one connection on the listener and sends the lines it reads on the returned channel.
+func acceptLines(t *testing.T, listener net.Listener) <-chan string {
+	t.Helper()
+
+	lines := make(chan string, 100)
+
+	go func() {
+		defer close(lines)
+
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		scanner := bufio.NewScanner(conn)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+	}()
+
+	return lines
+}
+
+// receive returns the next `count` values of the channel, failing the test after a timeout.
+func receive(t *testing.T, values <-chan string, count int) []string {
+	t.Helper()
+
+	received := make([]string, 0, count)
+
+	for len(received) < count {
+		select {
+		case value := <-values:
+			received = append(received, value)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("received %v, want %d values", received, count)
+		}
+	}
+
+	return received
+}
+
+// TestNetworkHandler_TCP tests that messages logged while the collector is down are sent once it is up.
+func TestNetworkHandler_TCP(t *testing.T) {
+	t.Parallel()
+
+	// Reserve an address, then stop listening to simulate a collector being down
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	address := listener.Addr().String()
+	listener.Close()
+
+	networkHandler := newTestNetworkHandler("tcp", address)
+	networkHandler.Log(levels.INFO, "queued while down")
+
+	time.Sleep(20 * time.Millisecond)
+
+	listener, err = net.Listen("tcp", address)
+	if err != nil {
+		t.Skipf("address %s was reused: %v", address, err)
+	}
+	defer listener.Close()
+
+	lines := acceptLines(t, listener)
+
+	networkHandler.Log(levels.ERROR, "sent when up")
+
+	if err := networkHandler.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"INFO queued while down", "ERROR sent when up"}
+	if got := receive(t, lines, 2); !reflect.DeepEqual(got, want) {
+		t.Errorf("lines = %v, want %v", got, want)
+	}
+}
+
+// TestNetworkHandler_Reconnect tests that messages logged after the collector closed the connection
+// are sent on a new connection, without losing any.
+func TestNetworkHandler_Reconnect(t *testing.T) {
+	t.Parallel()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	networkHandler := newTestNetworkHandler("tcp", listener.Addr().String())
+	networkHandler.Log(levels.INFO, "first connection")
+
+	conn, err := listener.Accept()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := conn.SetReadDeadline(time.Now().Add(5 * time.Second)); err != nil {
+		t.Fatal(err)
+	}
+
+	if line, err := bufio.NewReader(conn).ReadString('\n'); err != nil || line != "INFO first connection\n" {
+		t.Fatalf("line = %q, %v, want %q", line, err, "INFO first connection\n")
+	}
+
+	// Close the connection on the collector side, and let the handler notice it
+	conn.Close()
+	time.Sleep(50 * time.Millisecond)
+
+	lines := acceptLines(t, listener)
+
+	want := make([]string, 0, 10)
+
+	for i := range 10 {
+		networkHandler.Log(levels.INFO, "second connection "+strconv.Itoa(i))
+		want = append(want, "INFO second connection "+strconv.Itoa(i))
+	}
+
+	if err := networkHandler.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := receive(t, lines, len(want)); !reflect.DeepEqual(got, want) {
+		t.Errorf("lines = %v, want %v", got, want)
+	}
+}
+
+// TestNetworkHandler_OctetCounting tests the octet-counting framing.
+func TestNetworkHandler_OctetCounting(t *testing.T) {
+	t.Parallel()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	networkHandler := newTestNetworkHandler("tcp", listener.Addr().String())
+	networkHandler.SetFraming(handler.FramingOctetCounting)
+	networkHandler.Log(levels.INFO, "multi\nline")
+
+	if err := networkHandler.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	conn, err := listener.Accept()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	reader := bufio.NewReader(conn)
+
+	length, err := reader.ReadString(' ')
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	size, err := strconv.Atoi(strings.TrimSpace(length))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	message := make([]byte, size)
+	if _, err := io.ReadFull(reader, message); err != nil {
+		t.Fatal(err)
+	}
+
+	if string(message) != "INFO multi\nline" {
+		t.Errorf("message = %q, want %q", message, "INFO multi\nline")
+	}
+}
+
+// TestNetworkHandler_UDP tests that each message is sent in its own datagram.
+func TestNetworkHandler_UDP(t *testing.T) {
+	t.Parallel()
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	networkHandler := newTestNetworkHandler("udp", conn.LocalAddr().String())
+	networkHandler.Log(levels.WARN, "first")
+	networkHandler.Log(levels.WARN, "second")
+
+	if err := networkHandler.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	buffer := make([]byte, 1024)
+
+	for _, want := range []string{"WARN first", "WARN second"} {
+		if err := conn.SetReadDeadline(time.Now().Add(5 * time.Second)); err != nil {
+			t.Fatal(err)
+		}
+
+		size, _, err := conn.ReadFrom(buffer)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got := string(buffer[:size]); got != want {
+			t.Errorf("datagram = %q, want %q", got, want)
+		}
+	}
+}
+
+// TestNetworkHandler_ZeroQueueSize tests that a queue size below 1 still queues the message being logged.
+func TestNetworkHandler_ZeroQueueSize(t *testing.T) {
+	t.Parallel()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	lines := acceptLines(t, listener)
+
+	networkHandler := newTestNetworkHandler("tcp", listener.Addr().String())
+	networkHandler.SetQueueSize(0)
+	networkHandler.Log(levels.INFO, "queued")
+
+	if err := networkHandler.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := receive(t, lines, 1); got[0] != "INFO queued" {
+		t.Errorf("lines = %v, want [INFO queued]", got)
+	}
+}
@@ -0,0 +1,180 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/ZertyCraft/GoLogger/handler"
+	"github.com/ZertyCraft/GoLogger/levels"
+	"github.com/ZertyCraft/GoLogger/record"
+)
+
+// otlpExport is the part of an OTLP export request checked by the tests.
+type otlpExport struct {
+	ResourceLogs []struct {
+		Resource struct {
+			Attributes []map[string]any `json:"attributes"`
+		} `json:"resource"`
+		ScopeLogs []struct {
+			Scope      map[string]string `json:"scope"`
+			LogRecords []map[string]any  `json:"logRecords"`
+		} `json:"scopeLogs"`
+	} `json:"resourceLogs"`
+}
+
+// TestOTLPHandler_Export tests the mapping of records to the OpenTelemetry log data model.
+func TestOTLPHandler_Export(t *testing.T) {
+	t.Parallel()
+
+	requests := &httpRequests{}
+	server := httptest.NewServer(requests)
+
+	defer server.Close()
+
+	otlpHandler := handler.NewOTLPHandler(server.URL)
+	otlpHandler.SetServiceName("checkout")
+	otlpHandler.SetResourceAttribute("deployment.environment", "test")
+	otlpHandler.SetFlushInterval(time.Hour)
+	otlpHandler.SetErrorHandler(func(err error) { t.Error(err) })
+
+	rec := record.New(levels.WARN, "slow payment")
+	rec.Time = time.Unix(1700000000, 5)
+	rec.LoggerName = "payments"
+	rec.Fields = []record.Field{
+		{Key: "trace_id", Value: "5b8efff798038103d269b633813fc60c"},
+		{Key: "span_id", Value: "eee19b7ec3c1b174"},
+		{Key: "attempt", Value: 2},
+		{Key: "retried", Value: true},
+		{Key: "bytes", Value: uint(512)},
+		{Key: "checksum", Value: uint64(math.MaxUint64)},
+	}
+
+	logged := time.Now()
+
+	otlpHandler.LogRecord(rec)
+	otlpHandler.Log(levels.CRITICAL, "unscoped")
+
+	if err := otlpHandler.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	bodies := requests.Bodies()
+	if len(bodies) != 1 {
+		t.Fatalf("bodies = %v, want 1 body", bodies)
+	}
+
+	var export otlpExport
+	if err := json.Unmarshal([]byte(bodies[0]), &export); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(export.ResourceLogs) != 1 || len(export.ResourceLogs[0].ScopeLogs) != 2 {
+		t.Fatalf("export = %+v, want 1 resource with 2 scopes", export)
+	}
+
+	resourceLogs := export.ResourceLogs[0]
+
+	wantResource := []map[string]any{
+		{"key": "service.name", "value": map[string]any{"stringValue": "checkout"}},
+		{"key": "deployment.environment", "value": map[string]any{"stringValue": "test"}},
+	}
+	if !reflect.DeepEqual(resourceLogs.Resource.Attributes, wantResource) {
+		t.Errorf("resource attributes = %v, want %v", resourceLogs.Resource.Attributes, wantResource)
+	}
+
+	if resourceLogs.ScopeLogs[0].Scope["name"] != "payments" || resourceLogs.ScopeLogs[1].Scope["name"] != "" {
+		t.Errorf("scopes = %v and %v, want payments and the unnamed scope",
+			resourceLogs.ScopeLogs[0].Scope, resourceLogs.ScopeLogs[1].Scope)
+	}
+
+	logRecord := resourceLogs.ScopeLogs[0].LogRecords[0]
+
+	// The observed time is the time the handler received the record, not the time of the record
+	observed, err := strconv.ParseInt(fmt.Sprint(logRecord["observedTimeUnixNano"]), 10, 64)
+	if err != nil || observed < logged.UnixNano() {
+		t.Errorf("observedTimeUnixNano = %v, want a time after %d", logRecord["observedTimeUnixNano"], logged.UnixNano())
+	}
+
+	delete(logRecord, "observedTimeUnixNano")
+
+	wantRecord := map[string]any{
+		"timeUnixNano":   "1700000000000000005",
+		"severityNumber": float64(13),
+		"severityText":   "WARN",
+		"body":           map[string]any{"stringValue": "slow payment"},
+		"traceId":        "5b8efff798038103d269b633813fc60c",
+		"spanId":         "eee19b7ec3c1b174",
+		"attributes": []any{
+			map[string]any{"key": "attempt", "value": map[string]any{"intValue": "2"}},
+			map[string]any{"key": "retried", "value": map[string]any{"boolValue": true}},
+			map[string]any{"key": "bytes", "value": map[string]any{"intValue": "512"}},
+			map[string]any{"key": "checksum", "value": map[string]any{"stringValue": "18446744073709551615"}},
+		},
+	}
+	if !reflect.DeepEqual(logRecord, wantRecord) {
+		t.Errorf("log record = %v, want %v", logRecord, wantRecord)
+	}
+
+	if got := resourceLogs.ScopeLogs[1].LogRecords[0]["severityNumber"]; got != float64(21) {
+		t.Errorf("CRITICAL severityNumber = %v, want 21", got)
+	}
+
+	if got := requests.headers[0].Get("Content-Type"); got != "application/json" {
+		t.Errorf("Content-Type = %q, want %q", got, "application/json")
+	}
+}
+
+// TestOTLPHandler_InvalidIDs tests that trace and span ids that are not hexadecimal ids of the right length
+// are sent as attributes, so that the collector does not reject the batch.
+func TestOTLPHandler_InvalidIDs(t *testing.T) {
+	t.Parallel()
+
+	requests := &httpRequests{}
+	server := httptest.NewServer(requests)
+
+	defer server.Close()
+
+	otlpHandler := handler.NewOTLPHandler(server.URL)
+	otlpHandler.SetFlushInterval(time.Hour)
+	otlpHandler.SetErrorHandler(func(err error) { t.Error(err) })
+
+	rec := record.New(levels.INFO, "invalid ids")
+	rec.Fields = []record.Field{
+		{Key: "trace_id", Value: "not-a-trace-id-but-32-characters"},
+		{Key: "span_id", Value: "eee19b7e"},
+	}
+	otlpHandler.LogRecord(rec)
+
+	if err := otlpHandler.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	var export otlpExport
+	if err := json.Unmarshal([]byte(requests.Bodies()[0]), &export); err != nil {
+		t.Fatal(err)
+	}
+
+	logRecord := export.ResourceLogs[0].ScopeLogs[0].LogRecords[0]
+
+	if _, ok := logRecord["traceId"]; ok {
+		t.Errorf("traceId = %v, want none", logRecord["traceId"])
+	}
+
+	if _, ok := logRecord["spanId"]; ok {
+		t.Errorf("spanId = %v, want none", logRecord["spanId"])
+	}
+
+	wantAttributes := []any{
+		map[string]any{"key": "trace_id", "value": map[string]any{"stringValue": "not-a-trace-id-but-32-characters"}},
+		map[string]any{"key": "span_id", "value": map[string]any{"stringValue": "eee19b7e"}},
+	}
+	if !reflect.DeepEqual(logRecord["attributes"], wantAttributes) {
+		t.Errorf("attributes = %v, want %v", logRecord["attributes"], wantAttributes)
+	}
+}
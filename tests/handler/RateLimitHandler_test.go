@@ -0,0 +1,59 @@
+package handler_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ZertyCraft/GoLogger/handler"
+	"github.com/ZertyCraft/GoLogger/handler/handlertest"
+	"github.com/ZertyCraft/GoLogger/levels"
+)
+
+// TestRateLimitHandler_Drop tests that records over the limit of their level are dropped and counted.
+func TestRateLimitHandler_Drop(t *testing.T) {
+	t.Parallel()
+
+	recorder := handlertest.NewRecorder()
+	rateLimitHandler := handler.NewRateLimitHandler(recorder)
+	rateLimitHandler.SetLimit(levels.DEBUG, 0.001, 2)
+
+	for i := 0; i < 5; i++ {
+		rateLimitHandler.Log(levels.DEBUG, "debug")
+		rateLimitHandler.Log(levels.ERROR, "error")
+	}
+
+	if got := len(recorder.Records()); got != 7 {
+		t.Errorf("records = %d, want 7 (2 DEBUG and 5 ERROR)", got)
+	}
+
+	if got := rateLimitHandler.Dropped(levels.DEBUG); got != 3 {
+		t.Errorf("Dropped(DEBUG) = %d, want 3", got)
+	}
+
+	if got := rateLimitHandler.DroppedTotal(); got != 3 {
+		t.Errorf("DroppedTotal() = %d, want 3", got)
+	}
+}
+
+// TestRateLimitHandler_Summary tests that a summary is logged once the bucket is refilled.
+func TestRateLimitHandler_Summary(t *testing.T) {
+	t.Parallel()
+
+	recorder := handlertest.NewRecorder()
+	rateLimitHandler := handler.NewRateLimitHandler(recorder)
+	rateLimitHandler.SetLimit(levels.WARN, 100, 1)
+	rateLimitHandler.SetPolicy(handler.RateLimitSummary)
+
+	for i := 0; i < 3; i++ {
+		rateLimitHandler.Log(levels.WARN, "warn")
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	rateLimitHandler.Log(levels.WARN, "warn")
+
+	want := []string{"warn", "2 messages dropped by rate limit", "warn"}
+	if got := messagesOf(recorder.Records()); !reflect.DeepEqual(got, want) {
+		t.Errorf("messages = %v, want %v", got, want)
+	}
+}
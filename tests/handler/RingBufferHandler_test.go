@@ -0,0 +1,118 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ZertyCraft/GoLogger/formater"
+	"github.com/ZertyCraft/GoLogger/handler"
+	"github.com/ZertyCraft/GoLogger/levels"
+	"github.com/ZertyCraft/GoLogger/record"
+)
+
+// messagesOf returns the messages of the records.
+func messagesOf(records []record.Record) []string {
+	messages := make([]string, 0, len(records))
+	for _, rec := range records {
+		messages = append(messages, rec.Message)
+	}
+
+	return messages
+}
+
+// TestRingBufferHandler_Query tests that only the last records are kept and that they can be queried.
+func TestRingBufferHandler_Query(t *testing.T) {
+	t.Parallel()
+
+	ringBufferHandler := handler.NewRingBufferHandler(3)
+	start := time.Now()
+
+	for i, level := range []levels.Level{levels.DEBUG, levels.INFO, levels.ERROR, levels.WARN} {
+		rec := record.New(level, []string{"dropped", "info", "error", "warn"}[i])
+		rec.Time = start.Add(time.Duration(i) * time.Second)
+		ringBufferHandler.LogRecord(rec)
+	}
+
+	tests := []struct {
+		name  string
+		query handler.RecordQuery
+		want  []string
+	}{
+		{name: "All", query: handler.RecordQuery{}, want: []string{"info", "error", "warn"}},
+		{name: "MinLevel", query: handler.RecordQuery{MinLevel: levels.WARN}, want: []string{"error", "warn"}},
+		{name: "Since", query: handler.RecordQuery{Since: start.Add(2 * time.Second)}, want: []string{"error", "warn"}},
+		{name: "Until", query: handler.RecordQuery{Until: start.Add(2 * time.Second)}, want: []string{"info", "error"}},
+		{name: "Contains", query: handler.RecordQuery{Contains: "rr"}, want: []string{"error"}},
+		{name: "Limit", query: handler.RecordQuery{Limit: 1}, want: []string{"warn"}},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := messagesOf(ringBufferHandler.Query(test.query)); !reflect.DeepEqual(got, test.want) {
+				t.Errorf("Query() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+// TestRingBufferHandler_NegativeCapacity tests that a negative capacity keeps no record.
+func TestRingBufferHandler_NegativeCapacity(t *testing.T) {
+	t.Parallel()
+
+	ringBufferHandler := handler.NewRingBufferHandler(-1)
+	ringBufferHandler.Log(levels.ERROR, "dropped")
+
+	if got := ringBufferHandler.Snapshot(); len(got) != 0 {
+		t.Errorf("Snapshot() = %v, want no record", messagesOf(got))
+	}
+}
+
+// TestRingBufferHandler_ServeHTTP tests the JSON and text rendering of the records.
+func TestRingBufferHandler_ServeHTTP(t *testing.T) {
+	t.Parallel()
+
+	lineFormater := formater.NewLineFormater()
+	lineFormater.SetFormat("%l %m")
+
+	ringBufferHandler := handler.NewRingBufferHandler(10)
+	ringBufferHandler.SetFormater(lineFormater)
+	ringBufferHandler.Log(levels.INFO, "info")
+	ringBufferHandler.Log(levels.ERROR, "error")
+
+	response := httptest.NewRecorder()
+	ringBufferHandler.ServeHTTP(response, httptest.NewRequest(http.MethodGet, "/debug/logs?level=warn", nil))
+
+	var records []struct {
+		Level   string `json:"level"`
+		Message string `json:"message"`
+	}
+
+	if err := json.Unmarshal(response.Body.Bytes(), &records); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(records) != 1 || records[0].Level != "ERROR" || records[0].Message != "error" {
+		t.Errorf("JSON records = %+v, want the ERROR record", records)
+	}
+
+	response = httptest.NewRecorder()
+	ringBufferHandler.ServeHTTP(response, httptest.NewRequest(http.MethodGet, "/debug/logs?format=text", nil))
+
+	if got := response.Body.String(); got != "INFO info\nERROR error\n" {
+		t.Errorf("text records = `%v`, want `INFO info\\nERROR error\\n`", got)
+	}
+
+	response = httptest.NewRecorder()
+	ringBufferHandler.ServeHTTP(response, httptest.NewRequest(http.MethodGet, "/debug/logs?level=verbose", nil))
+
+	if response.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want %d", response.Code, http.StatusBadRequest)
+	}
+}
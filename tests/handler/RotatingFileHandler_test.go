@@ -4,7 +4,12 @@ import (
 	"bufio"
 	"fmt"
 	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/ZertyCraft/GoLogger/formater"
 	"github.com/ZertyCraft/GoLogger/handler"
@@ -89,3 +94,262 @@ func TestRotatingFileHandler_Log_NotLogged(t *testing.T) {
 		t.Error("Log file should not have been created")
 	}
 }
+
+func TestRotatingFileHandler_Hooks(t *testing.T) {
+	t.Parallel()
+
+	logFileName := "test_hooks.log"
+	directory := t.TempDir()
+
+	handler := initializeRotatingFileHandler(levels.INFO, logFileName)
+	handler.SetLogDirectory(directory)
+	handler.SetMaxFileSize(1)
+
+	var preRotatePaths, postRotatePaths []string
+
+	handler.SetPreRotateHook(func(oldPath string, newPath string) {
+		preRotatePaths = append(preRotatePaths, oldPath, newPath)
+	})
+	handler.SetPostRotateHook(func(oldPath string, newPath string) {
+		postRotatePaths = append(postRotatePaths, oldPath, newPath)
+
+		if _, err := os.Stat(oldPath); err != nil {
+			t.Errorf("New active file should exist in post rotate hook: %v", err)
+		}
+	})
+
+	handler.Log(levels.INFO, "This message is longer than the buffer size")
+	handler.Log(levels.INFO, "This message triggers a rotation")
+	handler.Flush()
+
+	wantPaths := []string{
+		filepath.Join(directory, logFileName),
+		filepath.Join(directory, logFileName+".1"),
+	}
+
+	if !reflect.DeepEqual(preRotatePaths, wantPaths) {
+		t.Errorf("Pre rotate hook paths = %v, want %v", preRotatePaths, wantPaths)
+	}
+
+	if !reflect.DeepEqual(postRotatePaths, wantPaths) {
+		t.Errorf("Post rotate hook paths = %v, want %v", postRotatePaths, wantPaths)
+	}
+}
+
+func TestRotatingFileHandler_FileLock(t *testing.T) {
+	t.Parallel()
+
+	const messageCount = 50
+
+	logFileName := "test_file_lock.log"
+	directory := t.TempDir()
+
+	// Two handlers on the same file behave like two processes, as each one opens its own lock file
+	handlers := make([]*handler.RotatingFileHandler, 2)
+	for i := range handlers {
+		handlers[i] = initializeRotatingFileHandler(levels.INFO, logFileName)
+		handlers[i].SetLogDirectory(directory)
+		handlers[i].SetMaxFileSize(256)
+		handlers[i].SetMaxBackupCount(messageCount)
+		handlers[i].SetUseFileLock(true)
+	}
+
+	var waitGroup sync.WaitGroup
+
+	for _, rotatingFileHandler := range handlers {
+		waitGroup.Add(1)
+
+		go func() {
+			defer waitGroup.Done()
+
+			for i := 0; i < messageCount; i++ {
+				rotatingFileHandler.Log(levels.INFO, fmt.Sprintf("message %02d", i))
+			}
+		}()
+	}
+
+	waitGroup.Wait()
+
+	files, err := os.ReadDir(directory)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lineCount := 0
+
+	for _, file := range files {
+		if !strings.HasPrefix(file.Name(), logFileName) || strings.HasSuffix(file.Name(), ".lock") {
+			continue
+		}
+
+		content, err := os.ReadFile(filepath.Join(directory, file.Name()))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, line := range strings.Split(strings.TrimSuffix(string(content), "\n"), "\n") {
+			if !strings.HasPrefix(line, "message ") {
+				t.Errorf("Corrupted line `%v` in %v", line, file.Name())
+			}
+
+			lineCount++
+		}
+	}
+
+	if lineCount != 2*messageCount {
+		t.Errorf("Found %d lines, want %d", lineCount, 2*messageCount)
+	}
+}
+
+func TestRotatingFileHandler_FlushPeriodically(t *testing.T) {
+	t.Parallel()
+
+	const messageCount = 200
+
+	logFileName := "test_flush_periodically.log"
+	directory := t.TempDir()
+
+	handler := initializeRotatingFileHandler(levels.INFO, logFileName)
+	handler.SetLogDirectory(directory)
+	handler.SetMaxFileSize(64)
+	handler.SetMaxBackupCount(messageCount)
+
+	// The background flusher uses the file while messages rotate it
+	handler.FlushPeriodically(time.Microsecond)
+
+	for i := 0; i < messageCount; i++ {
+		handler.Log(levels.INFO, fmt.Sprintf("message %03d", i))
+	}
+
+	if err := handler.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if lineCount := readDirectoryLines(t, directory); lineCount != messageCount {
+		t.Errorf("Line count = %d, want %d", lineCount, messageCount)
+	}
+}
+
+// readDirectoryLines returns the number of lines of the files in the directory.
+func readDirectoryLines(t *testing.T, directory string) int {
+	t.Helper()
+
+	files, err := os.ReadDir(directory)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lineCount := 0
+
+	for _, file := range files {
+		content, err := os.ReadFile(filepath.Join(directory, file.Name()))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		lineCount += strings.Count(string(content), "\n")
+	}
+
+	return lineCount
+}
+
+func TestRotatingFileHandler_HooksLog(t *testing.T) {
+	t.Parallel()
+
+	const messageCount = 5
+
+	logFileName := "test_hooks_log.log"
+	directory := t.TempDir()
+
+	handler := initializeRotatingFileHandler(levels.INFO, logFileName)
+	handler.SetLogDirectory(directory)
+	handler.SetMaxFileSize(1)
+	handler.SetMaxBackupCount(100)
+	handler.SetFlushLevel(levels.DEBUG)
+
+	hookCalls := 0
+
+	handler.SetPreRotateHook(func(_ string, newPath string) {
+		hookCalls++
+		handler.Log(levels.INFO, "rotating to "+newPath)
+	})
+	handler.SetPostRotateHook(func(_ string, newPath string) {
+		hookCalls++
+		handler.Log(levels.INFO, "rotated to "+newPath)
+	})
+
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		for i := 0; i < messageCount; i++ {
+			handler.Log(levels.INFO, fmt.Sprintf("message %d", i))
+		}
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Logging from a rotation hook deadlocked")
+	}
+
+	if err := handler.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if hookCalls == 0 {
+		t.Error("Hooks were not called")
+	}
+
+	if lineCount := readDirectoryLines(t, directory); lineCount != messageCount+hookCalls {
+		t.Errorf("Line count = %d, want %d", lineCount, messageCount+hookCalls)
+	}
+}
+
+func TestRotatingFileHandler_Symlink(t *testing.T) {
+	t.Parallel()
+
+	logFileName := "test_symlink.log"
+	directory := t.TempDir()
+
+	handler := initializeRotatingFileHandler(levels.INFO, logFileName)
+	handler.SetLogDirectory(directory)
+	handler.SetMaxFileSize(1)
+	handler.SetMaxBackupCount(1)
+	// The symlink name starts like the backups, so the cleanup must skip it
+	handler.SetSymlinkName(logFileName + ".current")
+
+	for _, message := range []string{"This message is longer than the buffer size", "first rotation", "second rotation"} {
+		handler.Log(levels.INFO, message)
+		handler.Flush()
+	}
+
+	if err := handler.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	symlinkPath := filepath.Join(directory, logFileName+".current")
+
+	target, err := os.Readlink(symlinkPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if target != logFileName {
+		t.Errorf("Symlink target = `%v`, want `%v`", target, logFileName)
+	}
+
+	content, err := os.ReadFile(symlinkPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(content) != "second rotation\n" {
+		t.Errorf("Content through the symlink = `%v`, want `second rotation\\n`", string(content))
+	}
+
+	if _, err := os.Lstat(symlinkPath + ".tmp"); !os.IsNotExist(err) {
+		t.Errorf("Temporary symlink should not exist: %v", err)
+	}
+}
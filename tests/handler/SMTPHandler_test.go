@@ -0,0 +1,216 @@
+package handler_test
+
+import (
+	"bufio"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ZertyCraft/GoLogger/formater"
+	"github.com/ZertyCraft/GoLogger/handler"
+	"github.com/ZertyCraft/GoLogger/levels"
+)
+
+// fakeSMTPServer is a minimal SMTP server recording the emails it receives.
+type fakeSMTPServer struct {
+	listener net.Listener
+	mutex    sync.Mutex
+	emails   []string
+}
+
+// newFakeSMTPServer starts a fake SMTP server on a local port, stopped at the end of the test.
+func newFakeSMTPServer(t *testing.T) *fakeSMTPServer {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := &fakeSMTPServer{listener: listener}
+
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+
+			go server.serve(conn)
+		}
+	}()
+
+	return server
+}
+
+// serve answers the commands of a client, recording the data of the emails.
+func (server *fakeSMTPServer) serve(conn net.Conn) {
+	defer conn.Close()
+
+	reader := bufio.NewReader(conn)
+	reply := func(line string) { conn.Write([]byte(line + "\r\n")) }
+
+	reply("220 localhost ready")
+
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+
+		command := strings.ToUpper(strings.TrimSpace(line))
+
+		switch {
+		case strings.HasPrefix(command, "EHLO"), strings.HasPrefix(command, "HELO"):
+			reply("250 localhost")
+		case strings.HasPrefix(command, "DATA"):
+			reply("354 go ahead")
+
+			var data strings.Builder
+
+			for {
+				dataLine, err := reader.ReadString('\n')
+				if err != nil {
+					return
+				}
+
+				if dataLine == ".\r\n" {
+					break
+				}
+
+				data.WriteString(dataLine)
+			}
+
+			server.mutex.Lock()
+			server.emails = append(server.emails, data.String())
+			server.mutex.Unlock()
+
+			reply("250 ok")
+		case strings.HasPrefix(command, "QUIT"):
+			reply("221 bye")
+
+			return
+		default:
+			reply("250 ok")
+		}
+	}
+}
+
+// Emails returns the data of the received emails.
+func (server *fakeSMTPServer) Emails() []string {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+
+	return append([]string(nil), server.emails...)
+}
+
+// waitEmails waits until the server received `count` emails, failing the test after a timeout.
+func (server *fakeSMTPServer) waitEmails(t *testing.T, count int) []string {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for len(server.Emails()) < count && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	emails := server.Emails()
+	if len(emails) != count {
+		t.Fatalf("received %d emails, want %d", len(emails), count)
+	}
+
+	return emails
+}
+
+// TestSMTPHandler_BatchAndThrottle tests that records of the window are sent in one email with their context,
+// and that the next records wait for the interval.
+func TestSMTPHandler_BatchAndThrottle(t *testing.T) {
+	t.Parallel()
+
+	server := newFakeSMTPServer(t)
+
+	lineFormater := formater.NewLineFormater()
+	lineFormater.SetFormat("%l %m")
+
+	smtpHandler := handler.NewSMTPHandler(server.listener.Addr().String(), "app@example.com", "oncall@example.com")
+	smtpHandler.SetFormater(lineFormater)
+	smtpHandler.SetWindow(20 * time.Millisecond)
+	smtpHandler.SetInterval(time.Hour)
+	smtpHandler.SetContextSize(2)
+	smtpHandler.SetErrorHandler(func(err error) { t.Error(err) })
+
+	smtpHandler.Log(levels.DEBUG, "dropped context")
+	smtpHandler.Log(levels.INFO, "connecting")
+	smtpHandler.Log(levels.ERROR, "connection refused")
+	smtpHandler.Log(levels.CRITICAL, "database down")
+	smtpHandler.Log(levels.CRITICAL, "giving up")
+
+	email := server.waitEmails(t, 1)[0]
+
+	for _, want := range []string{
+		"To: oncall@example.com\r\n",
+		"Subject: Log alert: CRITICAL database down (+1 more)\r\n",
+		"\r\n\r\nCRITICAL database down\r\nCRITICAL giving up\r\n\r\nContext:\r\nINFO connecting\r\nERROR connection refused\r\n",
+	} {
+		if !strings.Contains(email, want) {
+			t.Errorf("email = %q, want it to contain %q", email, want)
+		}
+	}
+
+	if strings.Contains(email, "dropped context") {
+		t.Errorf("email = %q, want only the last 2 context records", email)
+	}
+
+	smtpHandler.Log(levels.CRITICAL, "still down")
+	time.Sleep(50 * time.Millisecond)
+
+	if emails := server.Emails(); len(emails) != 1 {
+		t.Fatalf("received %d emails during the interval, want 1", len(emails))
+	}
+
+	if err := smtpHandler.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if email := server.waitEmails(t, 2)[1]; !strings.Contains(email, "CRITICAL still down") {
+		t.Errorf("email = %q, want the record logged during the interval", email)
+	}
+}
+
+// TestSMTPHandler_ZeroMaxRecords tests that a maximum number of records below 1 still sends the first record.
+func TestSMTPHandler_ZeroMaxRecords(t *testing.T) {
+	t.Parallel()
+
+	server := newFakeSMTPServer(t)
+
+	lineFormater := formater.NewLineFormater()
+	lineFormater.SetFormat("%l %m")
+
+	smtpHandler := handler.NewSMTPHandler(server.listener.Addr().String(), "app@example.com", "oncall@example.com")
+	smtpHandler.SetFormater(lineFormater)
+	smtpHandler.SetWindow(time.Hour)
+	smtpHandler.SetMaxRecords(0)
+	smtpHandler.SetErrorHandler(func(err error) { t.Error(err) })
+
+	smtpHandler.Log(levels.CRITICAL, "database down")
+	smtpHandler.Log(levels.CRITICAL, "giving up")
+
+	if err := smtpHandler.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	email := server.waitEmails(t, 1)[0]
+
+	for _, want := range []string{"CRITICAL database down\r\n", "... and 1 more records\r\n"} {
+		if !strings.Contains(email, want) {
+			t.Errorf("email = %q, want it to contain %q", email, want)
+		}
+	}
+
+	if err := smtpHandler.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,94 @@
+package handler_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ZertyCraft/GoLogger/handler"
+	"github.com/ZertyCraft/GoLogger/handler/handlertest"
+	"github.com/ZertyCraft/GoLogger/levels"
+)
+
+// TestSamplingHandler_LogRecord tests that the first records then every Mth one are logged,
+// followed by a summary of the suppressed ones.
+func TestSamplingHandler_LogRecord(t *testing.T) {
+	t.Parallel()
+
+	recorder := handlertest.NewRecorder()
+	samplingHandler := handler.NewSamplingHandler(recorder, time.Hour, 2, 3)
+
+	for i := 0; i < 8; i++ {
+		samplingHandler.Log(levels.WARN, "retrying")
+	}
+
+	samplingHandler.Log(levels.WARN, "other")
+
+	if err := samplingHandler.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Records 1, 2, 5 and 8 are logged, 3, 4, 6 and 7 are suppressed
+	want := []string{
+		"retrying", "retrying", "retrying", "retrying", "other",
+		"4 similar messages suppressed in the last 1h0m0s: retrying",
+	}
+
+	records := recorder.Records()
+	if got := messagesOf(records); !reflect.DeepEqual(got, want) {
+		t.Fatalf("messages = %v, want %v", got, want)
+	}
+
+	if suppressed, _ := records[len(records)-1].Field("sampling_suppressed"); suppressed != 4 {
+		t.Errorf("sampling_suppressed = %v, want 4", suppressed)
+	}
+}
+
+// TestSamplingHandler_Interval tests that counters are reset at the end of each interval.
+func TestSamplingHandler_Interval(t *testing.T) {
+	t.Parallel()
+
+	recorder := handlertest.NewRecorder()
+	samplingHandler := handler.NewSamplingHandler(recorder, 10*time.Millisecond, 1, 0)
+	t.Cleanup(func() { samplingHandler.Close() })
+
+	samplingHandler.Log(levels.WARN, "retrying")
+	samplingHandler.Log(levels.WARN, "retrying")
+
+	deadline := time.Now().Add(time.Second)
+	for len(recorder.Records()) < 2 {
+		if time.Now().After(deadline) {
+			t.Fatal("summary was not logged")
+		}
+
+		time.Sleep(time.Millisecond)
+	}
+
+	samplingHandler.Log(levels.WARN, "retrying")
+
+	recorder.AssertLogged(t, levels.WARN, "1 similar messages suppressed")
+
+	if got := len(recorder.Records()); got != 3 {
+		t.Errorf("records = %d, want 3", got)
+	}
+}
+
+// TestSamplingHandler_InvalidInterval tests that a non-positive interval falls back to the default one.
+func TestSamplingHandler_InvalidInterval(t *testing.T) {
+	t.Parallel()
+
+	recorder := handlertest.NewRecorder()
+	samplingHandler := handler.NewSamplingHandler(recorder, 0, 1, 0)
+
+	samplingHandler.Log(levels.WARN, "retrying")
+	samplingHandler.Log(levels.WARN, "retrying")
+
+	if err := samplingHandler.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"retrying", "1 similar messages suppressed in the last 1s: retrying"}
+	if got := messagesOf(recorder.Records()); !reflect.DeepEqual(got, want) {
+		t.Errorf("messages = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,228 @@
+package handler_test
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ZertyCraft/GoLogger/handler"
+	"github.com/ZertyCraft/GoLogger/levels"
+	"github.com/ZertyCraft/GoLogger/record"
+)
+
+// fakeHEC is a stand-in for a Splunk HTTP Event Collector with indexer acknowledgements,
+// confirming each acknowledgement on the second query.
+type fakeHEC struct {
+	mutex   sync.Mutex
+	events  []map[string]any
+	queries map[string]int  // The number of queries by ack id
+	lost    map[string]bool // The ack ids never confirmed
+}
+
+// ServeHTTP records the events and answers the acknowledgement queries.
+func (hec *fakeHEC) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
+	hec.mutex.Lock()
+	defer hec.mutex.Unlock()
+
+	if request.Header.Get("Authorization") != "Splunk secret" || request.Header.Get("X-Splunk-Request-Channel") != "channel" {
+		http.Error(writer, `{"text":"Invalid token","code":4}`, http.StatusForbidden)
+
+		return
+	}
+
+	switch request.URL.Path {
+	case "/services/collector/event":
+		scanner := bufio.NewScanner(request.Body)
+		for scanner.Scan() {
+			var event map[string]any
+			if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+				http.Error(writer, `{"text":"Invalid data format","code":6}`, http.StatusBadRequest)
+
+				return
+			}
+
+			hec.events = append(hec.events, event)
+		}
+
+		fmt.Fprintf(writer, `{"text":"Success","code":0,"ackId":%d}`, len(hec.queries))
+		hec.queries[fmt.Sprint(len(hec.queries))] = 0
+	case "/services/collector/ack":
+		body, _ := io.ReadAll(request.Body)
+
+		var query struct {
+			Acks []int64 `json:"acks"`
+		}
+
+		json.Unmarshal(body, &query)
+
+		acks := make(map[string]bool)
+
+		for _, ackID := range query.Acks {
+			id := fmt.Sprint(ackID)
+			hec.queries[id]++
+			acks[id] = hec.queries[id] >= 2 && !hec.lost[id]
+		}
+
+		json.NewEncoder(writer).Encode(map[string]any{"acks": acks})
+	default:
+		http.NotFound(writer, request)
+	}
+}
+
+// TestSplunkHECHandler_Envelope tests the envelope of the events and the wait for their acknowledgement.
+func TestSplunkHECHandler_Envelope(t *testing.T) {
+	t.Parallel()
+
+	hec := &fakeHEC{queries: make(map[string]int)}
+	server := httptest.NewServer(hec)
+
+	defer server.Close()
+
+	hecHandler := handler.NewSplunkHECHandler(server.URL, "secret")
+	hecHandler.SetHost("web-1")
+	hecHandler.SetSource("shop")
+	hecHandler.SetSourceType("_json")
+	hecHandler.SetIndex("main")
+	hecHandler.SetAckChannel("channel")
+	hecHandler.SetAckTimeout(5*time.Second, 5*time.Millisecond)
+	hecHandler.SetFlushInterval(time.Hour)
+	hecHandler.SetErrorHandler(func(err error) { t.Error(err) })
+
+	rec := record.New(levels.ERROR, "payment failed")
+	rec.Time = time.Unix(1700000000, 123456000)
+	rec.Fields = []record.Field{{Key: "order", Value: 42}}
+	hecHandler.LogRecord(rec)
+
+	if err := hecHandler.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	hec.mutex.Lock()
+	defer hec.mutex.Unlock()
+
+	if len(hec.events) != 1 {
+		t.Fatalf("events = %v, want 1 event", hec.events)
+	}
+
+	event := hec.events[0]
+
+	for key, want := range map[string]any{
+		"time": 1700000000.123456, "host": "web-1", "source": "shop", "sourcetype": "_json", "index": "main",
+		"fields": map[string]any{"order": "42"},
+	} {
+		if !reflect.DeepEqual(event[key], want) {
+			t.Errorf("%s = %v, want %v", key, event[key], want)
+		}
+	}
+
+	body, ok := event["event"].(map[string]any)
+	if !ok || body["message"] != "payment failed" || body["level"] != "ERROR" {
+		t.Errorf("event = %v, want the formatted record", event["event"])
+	}
+
+	if hec.queries["0"] != 2 {
+		t.Errorf("acknowledgement queries = %d, want 2", hec.queries["0"])
+	}
+}
+
+// TestSplunkHECHandler_AckTimeout tests that waiting for an acknowledgement does not delay the next batches,
+// and that a batch not acknowledged in time is sent again.
+func TestSplunkHECHandler_AckTimeout(t *testing.T) {
+	t.Parallel()
+
+	hec := &fakeHEC{queries: make(map[string]int), lost: map[string]bool{"0": true}}
+	server := httptest.NewServer(hec)
+
+	defer server.Close()
+
+	hecHandler := handler.NewSplunkHECHandler(server.URL, "secret")
+	hecHandler.SetAckChannel("channel")
+	hecHandler.SetAckTimeout(100*time.Millisecond, 5*time.Millisecond)
+	hecHandler.SetBatchSize(1, 1024)
+	hecHandler.SetFlushInterval(time.Hour)
+	hecHandler.SetErrorHandler(func(err error) { t.Error(err) })
+
+	hecHandler.Log(levels.INFO, "lost")
+	hecHandler.Log(levels.INFO, "next")
+
+	if err := hecHandler.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := hecHandler.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	hec.mutex.Lock()
+	defer hec.mutex.Unlock()
+
+	messages := make([]any, 0, len(hec.events))
+	for _, event := range hec.events {
+		messages = append(messages, event["event"].(map[string]any)["message"])
+	}
+
+	if want := []any{"lost", "next", "lost"}; !reflect.DeepEqual(messages, want) {
+		t.Errorf("messages = %v, want %v", messages, want)
+	}
+
+	if hecHandler.Unacknowledged() != 0 || hecHandler.Dropped() != 0 {
+		t.Errorf("Unacknowledged() = %d, Dropped() = %d, want 0", hecHandler.Unacknowledged(), hecHandler.Dropped())
+	}
+}
+
+// TestSplunkHECHandler_CloseUnacknowledged tests that Close reports the batches still not acknowledged
+// as failed deliveries, and never queues them on the closed queue.
+func TestSplunkHECHandler_CloseUnacknowledged(t *testing.T) {
+	t.Parallel()
+
+	hec := &fakeHEC{queries: make(map[string]int), lost: map[string]bool{"0": true}}
+	server := httptest.NewServer(hec)
+
+	defer server.Close()
+
+	var (
+		mutex sync.Mutex
+		errs  []error
+	)
+
+	hecHandler := handler.NewSplunkHECHandler(server.URL, "secret")
+	hecHandler.SetAckChannel("channel")
+	hecHandler.SetAckTimeout(20*time.Millisecond, 5*time.Millisecond)
+	hecHandler.SetMaxRetries(0)
+	hecHandler.SetFlushTimeout(10 * time.Millisecond)
+	hecHandler.SetFlushInterval(time.Hour)
+	hecHandler.SetErrorHandler(func(err error) {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		errs = append(errs, err)
+	})
+
+	hecHandler.Log(levels.INFO, "lost")
+
+	if err := hecHandler.Close(); err == nil {
+		t.Error("Close() = nil, want the records not acknowledged")
+	}
+
+	// Let a poller still running expire the batch
+	time.Sleep(50 * time.Millisecond)
+
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	if len(errs) != 1 || !errors.Is(errs[0], handler.ErrDeliveryFailed) {
+		t.Errorf("errors = %v, want one delivery failure", errs)
+	}
+
+	if hecHandler.Dropped() != 1 {
+		t.Errorf("Dropped() = %d, want 1", hecHandler.Dropped())
+	}
+}
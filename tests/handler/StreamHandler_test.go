@@ -5,7 +5,10 @@ import (
 	"errors"
 	"io"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/ZertyCraft/GoLogger/formater"
 	"github.com/ZertyCraft/GoLogger/handler"
@@ -124,3 +127,194 @@ func TestStreamHandler_Log_NotLogged(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+// readFile returns the content of the given file, failing the test on error.
+func readFile(t *testing.T, filePath string) string {
+	t.Helper()
+
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return string(content)
+}
+
+// newTestStreamHandler returns a StreamHandler writing `%m` lines to `fileName` in a temporary directory.
+func newTestStreamHandler(t *testing.T, fileName string) *handler.StreamHandler {
+	t.Helper()
+
+	lineFormater := formater.NewLineFormater()
+	lineFormater.SetFormat("%m")
+
+	streamHandler := handler.NewStreamHandler()
+	streamHandler.SetFormater(lineFormater)
+	streamHandler.SetLevel(levels.INFO)
+	streamHandler.SetLogDirectory(t.TempDir())
+	streamHandler.SetFileName(fileName)
+
+	return streamHandler
+}
+
+// TestStreamHandler_DefaultFormater test that the default formater is a `LineFormater` ("%d %l %m").
+func TestStreamHandler_DefaultFormater(t *testing.T) {
+	t.Parallel()
+
+	streamHandler := handler.NewStreamHandler()
+	streamHandler.SetLogDirectory(t.TempDir())
+	streamHandler.SetFileName("default_formater.log")
+	filePath := filepath.Join(streamHandler.GetLogDirectory(), "default_formater.log")
+
+	streamHandler.Log(levels.INFO, "default")
+
+	if err := streamHandler.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	line := readFile(t, filePath)
+
+	date, rest, _ := strings.Cut(line, " INFO ")
+	if _, err := time.Parse("2006-01-02 15:04:05", date); err != nil || rest != "default\n" {
+		t.Errorf("file = `%v`, want `<date> INFO default\\n`", line)
+	}
+}
+
+// TestStreamHandler_Reopen test that Reopen writes new messages to a new file after the log file was moved.
+func TestStreamHandler_Reopen(t *testing.T) {
+	t.Parallel()
+
+	streamHandler := newTestStreamHandler(t, "reopen.log")
+	filePath := filepath.Join(streamHandler.GetLogDirectory(), "reopen.log")
+
+	streamHandler.Log(levels.INFO, "before")
+
+	// Move the file like logrotate does
+	if err := os.Rename(filePath, filePath+".1"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := streamHandler.Reopen(); err != nil {
+		t.Fatal(err)
+	}
+
+	streamHandler.Log(levels.INFO, "after")
+	streamHandler.Flush()
+
+	if got := readFile(t, filePath+".1"); got != "before\n" {
+		t.Errorf("moved file = `%v`, want `before\\n`", got)
+	}
+
+	if got := readFile(t, filePath); got != "after\n" {
+		t.Errorf("reopened file = `%v`, want `after\\n`", got)
+	}
+}
+
+// TestStreamHandler_WatchFile test that the watcher reopens the log file after it was deleted.
+func TestStreamHandler_WatchFile(t *testing.T) {
+	t.Parallel()
+
+	streamHandler := newTestStreamHandler(t, "watch.log")
+	filePath := filepath.Join(streamHandler.GetLogDirectory(), "watch.log")
+
+	streamHandler.Log(levels.INFO, "before")
+	streamHandler.WatchFile(time.Millisecond)
+	t.Cleanup(streamHandler.StopWatchFile)
+
+	if err := os.Remove(filePath); err != nil {
+		t.Fatal(err)
+	}
+
+	// Wait for the watcher to recreate the file
+	deadline := time.Now().Add(time.Second)
+	for _, err := os.Stat(filePath); err != nil; _, err = os.Stat(filePath) {
+		if time.Now().After(deadline) {
+			t.Fatal("log file was not reopened")
+		}
+
+		time.Sleep(time.Millisecond)
+	}
+
+	streamHandler.Log(levels.INFO, "after")
+	streamHandler.Flush()
+
+	if got := readFile(t, filePath); got != "after\n" {
+		t.Errorf("reopened file = `%v`, want `after\\n`", got)
+	}
+}
+
+// TestStreamHandler_FlushLevel test that messages at or above the flush level are written without calling Flush.
+func TestStreamHandler_FlushLevel(t *testing.T) {
+	t.Parallel()
+
+	streamHandler := newTestStreamHandler(t, "flush_level.log")
+	streamHandler.SetFlushLevel(levels.ERROR)
+	streamHandler.SetSyncPolicy(handler.SyncOnFlush)
+	filePath := filepath.Join(streamHandler.GetLogDirectory(), "flush_level.log")
+
+	streamHandler.Log(levels.INFO, "buffered")
+
+	if got := readFile(t, filePath); got != "" {
+		t.Errorf("file = `%v`, want ``", got)
+	}
+
+	streamHandler.Log(levels.ERROR, "flushed")
+
+	if got := readFile(t, filePath); got != "buffered\nflushed\n" {
+		t.Errorf("file = `%v`, want `buffered\\nflushed\\n`", got)
+	}
+}
+
+// TestStreamHandler_FlushPeriodically test that the background flusher writes buffered messages.
+func TestStreamHandler_FlushPeriodically(t *testing.T) {
+	t.Parallel()
+
+	streamHandler := newTestStreamHandler(t, "flush_periodically.log")
+	filePath := filepath.Join(streamHandler.GetLogDirectory(), "flush_periodically.log")
+
+	streamHandler.Log(levels.INFO, "buffered")
+	streamHandler.FlushPeriodically(time.Millisecond)
+	t.Cleanup(streamHandler.StopFlushPeriodically)
+
+	deadline := time.Now().Add(time.Second)
+	for readFile(t, filePath) != "buffered\n" {
+		if time.Now().After(deadline) {
+			t.Fatal("buffered message was not flushed")
+		}
+
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// TestStreamHandler_Close test that Close writes buffered messages and that the handler is unusable afterwards.
+func TestStreamHandler_Close(t *testing.T) {
+	t.Parallel()
+
+	streamHandler := newTestStreamHandler(t, "close.log")
+	filePath := filepath.Join(streamHandler.GetLogDirectory(), "close.log")
+
+	var errs []error
+
+	streamHandler.SetErrorHandler(func(err error) {
+		errs = append(errs, err)
+	})
+
+	streamHandler.Log(levels.INFO, "before")
+
+	if err := streamHandler.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	streamHandler.Log(levels.INFO, "after")
+
+	if got := readFile(t, filePath); got != "before\n" {
+		t.Errorf("file = `%v`, want `before\\n`", got)
+	}
+
+	if len(errs) != 1 || !errors.Is(errs[0], handler.ErrClosed) {
+		t.Errorf("errors = %v, want [%v]", errs, handler.ErrClosed)
+	}
+
+	if err := streamHandler.Flush(); !errors.Is(err, handler.ErrClosed) {
+		t.Errorf("Flush() = %v, want %v", err, handler.ErrClosed)
+	}
+}
@@ -0,0 +1,136 @@
+package handler_test
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ZertyCraft/GoLogger/formater"
+	"github.com/ZertyCraft/GoLogger/handler"
+	"github.com/ZertyCraft/GoLogger/levels"
+	"github.com/ZertyCraft/GoLogger/record"
+)
+
+// newTestSyslogHandler returns a SyslogHandler with a fixed header and short timeouts.
+func newTestSyslogHandler(network string, address string) *handler.SyslogHandler {
+	syslogHandler := handler.NewSyslogHandler(network, address)
+	syslogHandler.SetBackoff(5*time.Millisecond, 20*time.Millisecond)
+	syslogHandler.SetErrorHandler(func(error) {})
+
+	rfc5424Formater := formater.NewRFC5424Formater()
+	rfc5424Formater.SetHostname("host")
+	rfc5424Formater.SetAppName("app")
+	rfc5424Formater.SetProcID("42")
+	syslogHandler.SetFormater(rfc5424Formater)
+
+	return syslogHandler
+}
+
+// TestSyslogHandler_Unixgram tests sending messages to a local syslog socket.
+func TestSyslogHandler_Unixgram(t *testing.T) {
+	t.Parallel()
+
+	// Unix socket paths are limited in length, so avoid the long test directory
+	directory, err := os.MkdirTemp("", "syslog")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(directory)
+
+	socketPath := filepath.Join(directory, "log")
+
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: socketPath, Net: "unixgram"})
+	if err != nil {
+		t.Skipf("unixgram sockets are not supported: %v", err)
+	}
+	defer conn.Close()
+
+	syslogHandler := newTestSyslogHandler("unixgram", socketPath)
+	syslogHandler.LogRecord(record.Record{
+		Time:       time.Now(),
+		Level:      levels.ERROR,
+		Message:    "disk full",
+		LoggerName: "",
+		Fields:     []record.Field{{Key: "disk", Value: "sda"}},
+		Caller:     record.Caller{File: "", Line: 0, Function: ""},
+	})
+
+	if err := syslogHandler.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := conn.SetReadDeadline(time.Now().Add(5 * time.Second)); err != nil {
+		t.Fatal(err)
+	}
+
+	buffer := make([]byte, 1024)
+
+	size, err := conn.Read(buffer)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := string(buffer[:size])
+
+	if !strings.HasPrefix(got, "<11>1 ") {
+		t.Errorf("message = %q, want the priority of user.err", got)
+	}
+
+	if want := ` host app 42 - [fields@32473 disk="sda"] disk full`; !strings.HasSuffix(got, want) {
+		t.Errorf("message = %q, want suffix %q", got, want)
+	}
+}
+
+// TestSyslogHandler_TCP tests that messages are sent with octet-counting framing over TCP.
+func TestSyslogHandler_TCP(t *testing.T) {
+	t.Parallel()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	syslogHandler := newTestSyslogHandler("tcp", listener.Addr().String())
+	syslogHandler.Log(levels.WARN, "first\nline")
+	syslogHandler.Log(levels.INFO, "second")
+
+	if err := syslogHandler.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	conn, err := listener.Accept()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	reader := bufio.NewReader(conn)
+
+	for _, want := range []string{"<12>1 ", "<14>1 "} {
+		length, err := reader.ReadString(' ')
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		size, err := strconv.Atoi(strings.TrimSpace(length))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		message := make([]byte, size)
+		if _, err := io.ReadFull(reader, message); err != nil {
+			t.Fatal(err)
+		}
+
+		if !strings.HasPrefix(string(message), want) {
+			t.Errorf("message = %q, want prefix %q", message, want)
+		}
+	}
+}
@@ -0,0 +1,275 @@
+package handler_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ZertyCraft/GoLogger/formater"
+	"github.com/ZertyCraft/GoLogger/handler"
+	"github.com/ZertyCraft/GoLogger/levels"
+)
+
+// newTestWriterHandler returns a WriterHandler writing messages only to the output.
+func newTestWriterHandler(output io.Writer) *handler.WriterHandler {
+	lineFormater := formater.NewLineFormater()
+	lineFormater.SetFormat("%m")
+
+	writerHandler := handler.NewWriterHandler(output)
+	writerHandler.SetFormater(lineFormater)
+	writerHandler.SetLevel(levels.DEBUG)
+
+	return writerHandler
+}
+
+// TestWriterHandler_Log test that messages are buffered until the handler is flushed.
+func TestWriterHandler_Log(t *testing.T) {
+	t.Parallel()
+
+	var output bytes.Buffer
+
+	writerHandler := newTestWriterHandler(&output)
+	writerHandler.Log(levels.INFO, "first")
+	writerHandler.Log(levels.INFO, "second\n")
+
+	if got := output.String(); got != "" {
+		t.Errorf("output = `%v`, want ``", got)
+	}
+
+	if err := writerHandler.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := output.String(); got != "first\nsecond\n" {
+		t.Errorf("output = `%v`, want `first\\nsecond\\n`", got)
+	}
+}
+
+// TestWriterHandler_FlushLevel test that messages at or above the flush level are written without calling Flush.
+func TestWriterHandler_FlushLevel(t *testing.T) {
+	t.Parallel()
+
+	var output bytes.Buffer
+
+	writerHandler := newTestWriterHandler(&output)
+	writerHandler.SetFlushLevel(levels.ERROR)
+
+	writerHandler.Log(levels.WARN, "buffered")
+	writerHandler.Log(levels.ERROR, "flushed")
+
+	if got := output.String(); got != "buffered\nflushed\n" {
+		t.Errorf("output = `%v`, want `buffered\\nflushed\\n`", got)
+	}
+}
+
+// TestWriterHandler_SetBufferSize test that the buffer size applies to a handler that already logged messages.
+func TestWriterHandler_SetBufferSize(t *testing.T) {
+	t.Parallel()
+
+	var output bytes.Buffer
+
+	writerHandler := newTestWriterHandler(&output)
+	writerHandler.Log(levels.INFO, "buffered")
+
+	writerHandler.SetBufferSize(1)
+
+	if got := output.String(); got != "buffered\n" {
+		t.Errorf("output = `%v`, want `buffered\\n`", got)
+	}
+
+	// A message larger than the buffer is written right away
+	writerHandler.Log(levels.INFO, "unbuffered")
+
+	if got := output.String(); got != "buffered\nunbuffered\n" {
+		t.Errorf("output = `%v`, want `buffered\\nunbuffered\\n`", got)
+	}
+}
+
+// TestWriterHandler_Concurrent test that concurrent messages are not interleaved.
+func TestWriterHandler_Concurrent(t *testing.T) {
+	t.Parallel()
+
+	var output bytes.Buffer
+
+	writerHandler := newTestWriterHandler(&output)
+	writerHandler.SetBufferSize(16)
+
+	message := strings.Repeat("x", 100)
+
+	var waitGroup sync.WaitGroup
+
+	for i := 0; i < 8; i++ {
+		waitGroup.Add(1)
+
+		go func() {
+			defer waitGroup.Done()
+
+			for j := 0; j < 50; j++ {
+				writerHandler.Log(levels.INFO, message)
+			}
+		}()
+	}
+
+	waitGroup.Wait()
+
+	if err := writerHandler.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := output.String(), strings.Repeat(message+"\n", 400); got != want {
+		t.Errorf("output has %d bytes of interleaved messages, want %d bytes", len(got), len(want))
+	}
+}
+
+// TestWriterHandler_Close test that Close flushes and closes the output when enabled.
+func TestWriterHandler_Close(t *testing.T) {
+	t.Parallel()
+
+	var output bytes.Buffer
+
+	gzipWriter := gzip.NewWriter(&output)
+
+	writerHandler := newTestWriterHandler(gzipWriter)
+	writerHandler.SetCloseOutput(true)
+
+	var errs []error
+
+	writerHandler.SetErrorHandler(func(err error) {
+		errs = append(errs, err)
+	})
+
+	writerHandler.Log(levels.INFO, "compressed")
+
+	if err := writerHandler.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	writerHandler.Log(levels.INFO, "after")
+
+	if len(errs) != 1 || !errors.Is(errs[0], handler.ErrClosed) {
+		t.Errorf("errors = %v, want [%v]", errs, handler.ErrClosed)
+	}
+
+	if err := writerHandler.Flush(); !errors.Is(err, handler.ErrClosed) {
+		t.Errorf("Flush() = %v, want %v", err, handler.ErrClosed)
+	}
+
+	// The gzip stream is only complete once the writer is closed
+	reader, err := gzip.NewReader(&output)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(got) != "compressed\n" {
+		t.Errorf("output = `%s`, want `compressed\\n`", got)
+	}
+}
+
+// syncingBuffer is an output with a `Sync` method counting the syncs and the data written before the last one.
+type syncingBuffer struct {
+	mutex  sync.Mutex
+	buffer bytes.Buffer
+	syncs  int
+	synced string
+}
+
+func (output *syncingBuffer) Write(data []byte) (int, error) {
+	output.mutex.Lock()
+	defer output.mutex.Unlock()
+
+	return output.buffer.Write(data)
+}
+
+func (output *syncingBuffer) Sync() error {
+	output.mutex.Lock()
+	defer output.mutex.Unlock()
+
+	output.syncs++
+	output.synced = output.buffer.String()
+
+	return nil
+}
+
+// state returns the number of syncs and the data written before the last one.
+func (output *syncingBuffer) state() (int, string) {
+	output.mutex.Lock()
+	defer output.mutex.Unlock()
+
+	return output.syncs, output.synced
+}
+
+// TestWriterHandler_SyncPolicies test when the output is synced with SyncNever and SyncOnFlush.
+func TestWriterHandler_SyncPolicies(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		name   string
+		policy handler.SyncPolicy
+		syncs  int
+	}{
+		{name: "SyncNever", policy: handler.SyncNever, syncs: 0},
+		{name: "SyncOnFlush", policy: handler.SyncOnFlush, syncs: 1},
+	} {
+		output := &syncingBuffer{}
+
+		writerHandler := newTestWriterHandler(output)
+		writerHandler.SetSyncPolicy(test.policy)
+		writerHandler.Log(levels.INFO, "written")
+
+		if err := writerHandler.Flush(); err != nil {
+			t.Fatal(err)
+		}
+
+		// Nothing was written since the last sync
+		if err := writerHandler.Flush(); err != nil {
+			t.Fatal(err)
+		}
+
+		if syncs, _ := output.state(); syncs != test.syncs {
+			t.Errorf("%s: syncs = %d, want %d", test.name, syncs, test.syncs)
+		}
+	}
+}
+
+// TestWriterHandler_SyncPeriodic test that SyncPeriodic writes and syncs buffered messages without any flush,
+// and stops once the handler is closed.
+func TestWriterHandler_SyncPeriodic(t *testing.T) {
+	t.Parallel()
+
+	output := &syncingBuffer{}
+
+	writerHandler := newTestWriterHandler(output)
+	writerHandler.SetSyncInterval(time.Millisecond)
+	writerHandler.SetSyncPolicy(handler.SyncPeriodic)
+	writerHandler.Log(levels.INFO, "burst")
+
+	deadline := time.Now().Add(5 * time.Second)
+	for _, synced := output.state(); synced != "burst\n"; _, synced = output.state() {
+		if time.Now().After(deadline) {
+			t.Fatal("buffered message was not synced")
+		}
+
+		time.Sleep(time.Millisecond)
+	}
+
+	if err := writerHandler.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	syncs, _ := output.state()
+	time.Sleep(20 * time.Millisecond)
+
+	if after, _ := output.state(); after != syncs {
+		t.Errorf("syncs = %d after Close, want %d", after, syncs)
+	}
+}
@@ -0,0 +1,135 @@
+package handlertest_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/ZertyCraft/GoLogger/handler"
+	"github.com/ZertyCraft/GoLogger/handler/handlertest"
+	"github.com/ZertyCraft/GoLogger/levels"
+	"github.com/ZertyCraft/GoLogger/logger"
+	"github.com/ZertyCraft/GoLogger/record"
+)
+
+// `fakeT` records the errors reported by the assertion helpers, the logged lines and the cleanup functions.
+type fakeT struct {
+	testing.TB
+	errors   []string
+	lines    []string
+	cleanups []func()
+}
+
+func (t *fakeT) Helper() {}
+
+func (t *fakeT) Errorf(format string, args ...any) {
+	t.errors = append(t.errors, fmt.Sprintf(format, args...))
+}
+
+func (t *fakeT) Log(args ...any) {
+	t.lines = append(t.lines, fmt.Sprint(args...))
+}
+
+func (t *fakeT) Cleanup(cleanup func()) {
+	t.cleanups = append(t.cleanups, cleanup)
+}
+
+// `complete` runs the cleanup functions, as when the test completes.
+func (t *fakeT) complete() {
+	for i := len(t.cleanups) - 1; i >= 0; i-- {
+		t.cleanups[i]()
+	}
+}
+
+// TestRecorder_Records tests that the recorder stores full records.
+func TestRecorder_Records(t *testing.T) {
+	t.Parallel()
+
+	recorder := handlertest.NewRecorder()
+
+	log := logger.NewLogger()
+	log.SetName("app")
+	log.AddHandler(recorder)
+	log.Debug("debug message", record.Field{Key: "user", Value: "alice"})
+
+	records := recorder.Records()
+	if len(records) != 1 {
+		t.Fatalf("Records() = %v, want 1 record", records)
+	}
+
+	if user, _ := records[0].Field("user"); records[0].LoggerName != "app" || user != "alice" {
+		t.Errorf("Records()[0] = %+v, want logger `app` and user `alice`", records[0])
+	}
+
+	recorder.Reset()
+
+	if records := recorder.Records(); len(records) != 0 {
+		t.Errorf("Records() after Reset = %v, want none", records)
+	}
+}
+
+// TestRecorder_Assertions tests that the assertion helpers report errors only when they should.
+func TestRecorder_Assertions(t *testing.T) {
+	t.Parallel()
+
+	recorder := handlertest.NewRecorder()
+	recorder.Log(levels.ERROR, "connection refused")
+
+	passing := &fakeT{}
+	recorder.AssertLogged(passing, levels.ERROR, "refused")
+	recorder.AssertNotLogged(passing, levels.WARN, "refused")
+
+	if len(passing.errors) != 0 {
+		t.Errorf("passing assertions reported %v", passing.errors)
+	}
+
+	failing := &fakeT{}
+	recorder.AssertLogged(failing, levels.INFO, "refused")
+	recorder.AssertNotLogged(failing, levels.ERROR, "connection")
+
+	if len(failing.errors) != 2 {
+		t.Errorf("failing assertions reported %v, want 2 errors", failing.errors)
+	}
+}
+
+// TestRecorder_LevelAndFilters tests that the recorder only stores the records allowed by its level and filters.
+func TestRecorder_LevelAndFilters(t *testing.T) {
+	t.Parallel()
+
+	recorder := handlertest.NewRecorder()
+	recorder.SetLevel(levels.INFO)
+	recorder.AddFilter(handler.MessageContains("kept"))
+
+	recorder.Log(levels.DEBUG, "kept debug")
+	recorder.Log(levels.INFO, "dropped info")
+	recorder.Log(levels.WARN, "kept warn")
+
+	if records := recorder.Records(); len(records) != 1 || records[0].Message != "kept warn" {
+		t.Errorf("Records() = %v, want only `kept warn`", records)
+	}
+}
+
+// TestTestingHandler_Log tests that records are written with the log of the test,
+// and dropped once the test completed.
+func TestTestingHandler_Log(t *testing.T) {
+	t.Parallel()
+
+	fake := &fakeT{}
+
+	testingHandler := handlertest.NewTestingHandler(fake)
+	testingHandler.Log(levels.INFO, "connected")
+
+	if len(fake.lines) != 1 || fake.lines[0] != "INFO connected" {
+		t.Errorf("lines = %q, want [%q]", fake.lines, "INFO connected")
+	}
+
+	fake.complete()
+	testingHandler.Log(levels.ERROR, "logged after the test")
+
+	if len(fake.lines) != 1 {
+		t.Errorf("lines after the test completed = %q, want only the first line", fake.lines)
+	}
+
+	if len(fake.errors) != 0 {
+		t.Errorf("errors = %v, want none", fake.errors)
+	}
+}
@@ -0,0 +1,142 @@
+package logger_test
+
+import (
+	"errors"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/ZertyCraft/GoLogger/handler/handlertest"
+	"github.com/ZertyCraft/GoLogger/levels"
+	"github.com/ZertyCraft/GoLogger/logger"
+)
+
+// `testHandler` is a handler recording its calls, returning `err` from Flush and Close.
+type testHandler struct {
+	messages []string
+	flushed  int
+	closed   int
+	err      error
+}
+
+func (h *testHandler) Log(_ levels.Level, message string) {
+	h.messages = append(h.messages, message)
+}
+
+func (h *testHandler) Flush() error {
+	h.flushed++
+
+	return h.err
+}
+
+func (h *testHandler) Close() error {
+	h.closed++
+
+	return h.err
+}
+
+// `flushOnlyHandler` is a handler that can be flushed but not closed.
+type flushOnlyHandler struct {
+	flushed int
+}
+
+func (h *flushOnlyHandler) Log(_ levels.Level, _ string) {}
+
+func (h *flushOnlyHandler) Flush() error {
+	h.flushed++
+
+	return nil
+}
+
+// TestLogger_Sync tests that Sync flushes every handler and joins their errors.
+func TestLogger_Sync(t *testing.T) {
+	t.Parallel()
+
+	errFirst := errors.New("first")
+	errSecond := errors.New("second")
+
+	first := &testHandler{err: errFirst}
+	second := &testHandler{err: errSecond}
+
+	log := logger.NewLogger()
+	log.AddHandler(first)
+	log.AddHandler(second)
+
+	err := log.Sync()
+	if !errors.Is(err, errFirst) || !errors.Is(err, errSecond) {
+		t.Errorf("Sync() = %v, want both handler errors", err)
+	}
+
+	if first.flushed != 1 || second.flushed != 1 {
+		t.Errorf("Flush calls = %d and %d, want 1 and 1", first.flushed, second.flushed)
+	}
+}
+
+// TestLogger_Close tests that Close closes or flushes every handler once and drops later messages.
+func TestLogger_Close(t *testing.T) {
+	t.Parallel()
+
+	closer := &testHandler{}
+	flusher := &flushOnlyHandler{}
+
+	log := logger.NewLogger()
+	log.AddHandler(closer)
+	log.AddHandler(flusher)
+
+	if err := log.Close(); err != nil {
+		t.Fatalf("Close() = %v, want nil", err)
+	}
+
+	if err := log.Close(); err != nil {
+		t.Fatalf("second Close() = %v, want nil", err)
+	}
+
+	log.Info("dropped")
+
+	if closer.closed != 1 || closer.flushed != 0 {
+		t.Errorf("Close and Flush calls = %d and %d, want 1 and 0", closer.closed, closer.flushed)
+	}
+
+	if flusher.flushed != 1 {
+		t.Errorf("Flush calls = %d, want 1", flusher.flushed)
+	}
+
+	if len(closer.messages) != 0 {
+		t.Errorf("Messages = %v, want none", closer.messages)
+	}
+}
+
+// TestLogger_SetReportCaller tests that the location of the calls is added to the records when enabled.
+func TestLogger_SetReportCaller(t *testing.T) {
+	t.Parallel()
+
+	recorder := handlertest.NewRecorder()
+
+	log := logger.NewLogger()
+	log.AddHandler(recorder)
+	log.Info("without caller")
+	log.SetReportCaller(true)
+
+	_, file, line, _ := runtime.Caller(0)
+	log.Info("from Info")
+	log.Log(levels.INFO, "from Log")
+
+	records := recorder.Records()
+	if len(records) != 3 {
+		t.Fatalf("len(Records()) = %d, want 3", len(records))
+	}
+
+	if records[0].HasCaller() {
+		t.Errorf("Caller = %v, want none", records[0].Caller)
+	}
+
+	for i, rec := range records[1:] {
+		if rec.Caller.File != file || rec.Caller.Line != line+1+i {
+			t.Errorf("Caller = %s:%d, want %s:%d", rec.Caller.File, rec.Caller.Line, file, line+1+i)
+		}
+
+		if !strings.HasSuffix(rec.Caller.Function, "TestLogger_SetReportCaller") {
+			t.Errorf("Caller.Function = %q, want the test function", rec.Caller.Function)
+		}
+	}
+}